@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/controller/cost"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// catalog are the Toppings by name.
+type catalog map[string]*v1alpha1.Topping
+
+func loadCatalog(ctx context.Context, clientset versioned.Interface) (catalog, error) {
+	list, err := clientset.RestaurantV1alpha1().Toppings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list toppings: %w", err)
+	}
+	c := catalog{}
+	for i := range list.Items {
+		c[list.Items[i].Name] = &list.Items[i]
+	}
+	return c, nil
+}
+
+// names returns the topping names in alphabetical order.
+func (c catalog) names() []string {
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolvedTopping is a topping of a pizza with its price from the catalog.
+type resolvedTopping struct {
+	Name     string  `json:"name"`
+	Quantity int     `json:"quantity"`
+	Price    float64 `json:"price"`
+	Cost     float64 `json:"cost"`
+	// Missing is true if the topping is not in the catalog.
+	Missing bool `json:"missing,omitempty"`
+}
+
+// resolve returns the toppings of a pizza with their prices for the size of
+// the pizza, and the cost of all toppings found in the catalog. The toppings
+// are resolved like by the cost controller, so the cost of toppings of
+// sections is pro-rated by the coverage of the sections.
+func (c catalog) resolve(pizza *v1beta1.Pizza) ([]resolvedTopping, float64) {
+	toppings, err := cost.Resolve(pizza, catalogLister(c))
+	if err != nil {
+		return nil, 0
+	}
+	resolved := make([]resolvedTopping, 0, len(toppings))
+	for _, t := range toppings {
+		r := resolvedTopping{Name: t.Topping.Name, Quantity: int(t.Quantity)}
+		if _, ok := c[t.Topping.Name]; ok {
+			r.Price = t.UnitCost
+			r.Cost = t.UnitCost * t.Portions
+		} else {
+			r.Missing = true
+		}
+		resolved = append(resolved, r)
+	}
+	return resolved, toppings.Cost()
+}
+
+// catalogLister looks toppings up in the catalog. Toppings not in the catalog
+// are returned without a cost, so that the cost of a pizza is the cost of
+// the toppings found.
+type catalogLister catalog
+
+var _ restaurantv1alpha1.ToppingLister = catalogLister{}
+
+func (c catalogLister) List(selector labels.Selector) ([]*v1alpha1.Topping, error) {
+	var toppings []*v1alpha1.Topping
+	for _, t := range c {
+		if selector.Matches(labels.Set(t.Labels)) {
+			toppings = append(toppings, t)
+		}
+	}
+	return toppings, nil
+}
+
+func (c catalogLister) Get(name string) (*v1alpha1.Topping, error) {
+	if t, ok := c[name]; ok {
+		return t, nil
+	}
+	return &v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil
+}
+
+// missing returns the names of the toppings of pizza not in the catalog,
+// including those resolved from its recipe.
+func (c catalog) missing(pizza *v1beta1.Pizza) []string {
+	var names []string
+	for _, t := range pizza.AllToppings() {
+		if _, ok := c[t.Name]; !ok {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
+
+// formatToppings returns the toppings like "2x salami, tomato".
+func formatToppings(toppings []v1beta1.PizzaTopping) string {
+	parts := make([]string, 0, len(toppings))
+	for _, t := range toppings {
+		if t.Quantity == 1 {
+			parts = append(parts, t.Name)
+		} else {
+			parts = append(parts, fmt.Sprintf("%dx %s", t.Quantity, t.Name))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
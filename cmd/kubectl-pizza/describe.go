@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/cli"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pizzaDescription is the output of describe for -o json and yaml.
+type pizzaDescription struct {
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Created   metav1.Time       `json:"created"`
+	Toppings  []resolvedTopping `json:"toppings"`
+	// Cost is the cost computed from the current topping prices.
+	Cost float64 `json:"cost"`
+	// StatusCost is the cost recorded in the status of the Pizza.
+	StatusCost float64 `json:"statusCost,omitempty"`
+	// Discounts are the discounts of promotions recorded in the status of
+	// the Pizza, which the status cost includes.
+	Discounts []v1beta1.AppliedDiscount `json:"discounts,omitempty"`
+	// Complete is false if some toppings are not on the menu.
+	Complete bool `json:"complete"`
+	// DietaryClaims are the diets the Pizza claims to be suitable for.
+	DietaryClaims []v1beta1.DietaryClaim `json:"dietaryClaims,omitempty"`
+	// Allergens are the allergens recorded in the status of the Pizza.
+	Allergens []v1beta1.Allergen `json:"allergens,omitempty"`
+}
+
+type describeOptions struct {
+	ClientFlags *cli.ClientFlags
+	Output      string
+}
+
+func newDescribeCommand(clientFlags *cli.ClientFlags) *cobra.Command {
+	o := &describeOptions{ClientFlags: clientFlags}
+	cmd := &cobra.Command{
+		Use:   "describe PIZZA...",
+		Short: "Show the toppings of pizzas with their prices",
+		Long: `Describe shows the toppings of Pizzas with the current price of each topping
+and the resulting cost, and the discounts of promotions the Pizza got. With
+-o json or yaml the same information is printed in a structured form. Wide
+output is the same as the default.`,
+		Example: `  kubectl pizza describe margherita`,
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(cmd.Context(), args, os.Stdout)
+		},
+	}
+	addOutputFlag(cmd.Flags(), &o.Output)
+	return cmd
+}
+
+func (o *describeOptions) Run(ctx context.Context, names []string, out io.Writer) error {
+	if err := validateOutput(o.Output); err != nil {
+		return err
+	}
+	namespace, err := o.ClientFlags.Namespace()
+	if err != nil {
+		return err
+	}
+	config, err := o.ClientFlags.RESTConfig()
+	if err != nil {
+		return err
+	}
+	clientset, err := versioned.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	c, err := loadCatalog(ctx, clientset)
+	if err != nil {
+		return err
+	}
+
+	descriptions := make([]pizzaDescription, 0, len(names))
+	for _, name := range names {
+		pizza, err := clientset.RestaurantV1beta1().Pizzas(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		descriptions = append(descriptions, describePizza(pizza, c))
+	}
+
+	var obj interface{} = descriptions
+	if len(descriptions) == 1 {
+		obj = descriptions[0]
+	}
+	if ok, err := printStructured(out, o.Output, obj); ok {
+		return err
+	}
+	for i, d := range descriptions {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		if err := printDescription(out, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func describePizza(pizza *v1beta1.Pizza, c catalog) pizzaDescription {
+	toppings, cost := c.resolve(pizza)
+	d := pizzaDescription{
+		Namespace:  pizza.Namespace,
+		Name:       pizza.Name,
+		Labels:     pizza.Labels,
+		Created:    pizza.CreationTimestamp,
+		Toppings:   toppings,
+		Cost:       cost,
+		StatusCost: pizza.Status.Cost,
+		Complete:   len(c.missing(pizza)) == 0,
+
+		DietaryClaims: pizza.Spec.DietaryClaims,
+		Allergens:     pizza.Status.Allergens,
+	}
+	if b := pizza.Status.CostBreakdown; b != nil {
+		d.Discounts = b.Discounts
+	}
+	return d
+}
+
+func printDescription(out io.Writer, d pizzaDescription) error {
+	w := newTabWriter(out)
+	fmt.Fprintf(w, "Name:\t%s\n", d.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", d.Namespace)
+	fmt.Fprintf(w, "Labels:\t%s\n", formatLabels(d.Labels))
+	fmt.Fprintf(w, "Created:\t%s (%s ago)\n", d.Created.Format("Mon, 02 Jan 2006 15:04:05 -0700"), age(d.Created))
+	fmt.Fprintf(w, "Toppings:\n")
+	fmt.Fprintf(w, "  NAME\tQUANTITY\tPRICE\tCOST\n")
+	for _, t := range d.Toppings {
+		if t.Missing {
+			fmt.Fprintf(w, "  %s\t%d\t<not on menu>\t<unknown>\n", t.Name, t.Quantity)
+			continue
+		}
+		fmt.Fprintf(w, "  %s\t%d\t%s\t%s\n", t.Name, t.Quantity, formatCost(t.Price), formatCost(t.Cost))
+	}
+	if d.Complete {
+		fmt.Fprintf(w, "Cost:\t%s\n", formatCost(d.Cost))
+	} else {
+		fmt.Fprintf(w, "Cost:\t<unknown>, at least %s\n", formatCost(d.Cost))
+	}
+	for _, discount := range d.Discounts {
+		fmt.Fprintf(w, "Discount:\t-%s (%s)\n", formatCost(discount.Amount), discount.Promotion)
+	}
+	if d.StatusCost != 0 && (d.StatusCost != d.Cost || len(d.Discounts) > 0) {
+		fmt.Fprintf(w, "Status Cost:\t%s\n", formatCost(d.StatusCost))
+	}
+	fmt.Fprintf(w, "Dietary Claims:\t%s\n", formatList(d.DietaryClaims))
+	fmt.Fprintf(w, "Allergens:\t%s\n", formatList(d.Allergens))
+	return w.Flush()
+}
+
+// formatList joins the items of a list, or returns <none>.
+func formatList[T ~string](items []T) string {
+	if len(items) == 0 {
+		return "<none>"
+	}
+	strs := make([]string, 0, len(items))
+	for _, item := range items {
+		strs = append(strs, string(item))
+	}
+	return strings.Join(strs, ", ")
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "<none>"
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
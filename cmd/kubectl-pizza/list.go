@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/cli"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type listOptions struct {
+	ClientFlags   *cli.ClientFlags
+	AllNamespaces bool
+	Selector      string
+	Output        string
+}
+
+func newListCommand(clientFlags *cli.ClientFlags) *cobra.Command {
+	o := &listOptions{ClientFlags: clientFlags}
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List pizzas with their toppings and cost",
+		Long: `List prints the Pizzas of a namespace with their toppings and the cost
+computed from the current topping prices. Toppings which are not on the menu
+are not counted, and the cost of their pizza is shown as <unknown>.`,
+		Example: `  # List the pizzas of all namespaces
+  kubectl pizza list -A
+
+  # List the pizzas of the current namespace with the number of pieces
+  kubectl pizza list -o wide`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(cmd.Context(), os.Stdout)
+		},
+	}
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "List the pizzas of all namespaces.")
+	cmd.Flags().StringVarP(&o.Selector, "selector", "l", o.Selector, "Label selector to filter on.")
+	addOutputFlag(cmd.Flags(), &o.Output)
+	return cmd
+}
+
+func (o *listOptions) Run(ctx context.Context, out io.Writer) error {
+	if err := validateOutput(o.Output); err != nil {
+		return err
+	}
+	namespace := metav1.NamespaceAll
+	if !o.AllNamespaces {
+		var err error
+		if namespace, err = o.ClientFlags.Namespace(); err != nil {
+			return err
+		}
+	}
+	config, err := o.ClientFlags.RESTConfig()
+	if err != nil {
+		return err
+	}
+	clientset, err := versioned.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	list, err := clientset.RestaurantV1beta1().Pizzas(namespace).List(ctx, metav1.ListOptions{LabelSelector: o.Selector})
+	if err != nil {
+		return fmt.Errorf("failed to list pizzas: %w", err)
+	}
+	list.SetGroupVersionKind(pizzaListKind)
+	for i := range list.Items {
+		list.Items[i].SetGroupVersionKind(pizzaKind)
+	}
+	if ok, err := printStructured(out, o.Output, list); ok {
+		return err
+	}
+
+	if len(list.Items) == 0 {
+		if o.AllNamespaces {
+			fmt.Fprintln(os.Stderr, "No pizzas found.")
+		} else {
+			fmt.Fprintf(os.Stderr, "No pizzas found in %s namespace.\n", namespace)
+		}
+		return nil
+	}
+	c, err := loadCatalog(ctx, clientset)
+	if err != nil {
+		return err
+	}
+	return o.printTable(out, list.Items, c)
+}
+
+func (o *listOptions) printTable(out io.Writer, pizzas []v1beta1.Pizza, c catalog) error {
+	w := newTabWriter(out)
+	var columns []string
+	if o.AllNamespaces {
+		columns = append(columns, "NAMESPACE")
+	}
+	columns = append(columns, "NAME", "TOPPINGS", "COST", "AGE")
+	if o.Output == outputWide {
+		columns = append(columns, "PIECES", "NOT ON MENU")
+	}
+	fmt.Fprintln(w, strings.Join(columns, "\t"))
+
+	for i := range pizzas {
+		pizza := &pizzas[i]
+		var row []string
+		if o.AllNamespaces {
+			row = append(row, pizza.Namespace)
+		}
+		_, cost := c.resolve(pizza)
+		missing := c.missing(pizza)
+		costColumn := formatCost(cost)
+		if len(missing) > 0 {
+			costColumn = "<unknown>"
+		}
+		row = append(row, pizza.Name, formatToppings(pizza.AllToppings()), costColumn, age(pizza.CreationTimestamp))
+		if o.Output == outputWide {
+			pieces := 0
+			for _, t := range pizza.AllToppings() {
+				pieces += t.Quantity
+			}
+			notOnMenu := "<none>"
+			if len(missing) > 0 {
+				notOnMenu = strings.Join(missing, ",")
+			}
+			row = append(row, fmt.Sprint(pieces), notOnMenu)
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,35 @@
+// Command kubectl-pizza is a kubectl plugin for everyday work with Pizzas and
+// Toppings. Install it on the PATH and call it as kubectl pizza.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zeroisme/pizza-crd/pkg/cli"
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	clientFlags := cli.NewClientFlags()
+	cmd := &cobra.Command{
+		Use:           "kubectl-pizza",
+		Short:         "Order, inspect and change pizzas",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	clientFlags.AddFlags(cmd.PersistentFlags())
+	cmd.AddCommand(newListCommand(clientFlags))
+	cmd.AddCommand(newDescribeCommand(clientFlags))
+	cmd.AddCommand(newAddToppingCommand(clientFlags))
+	cmd.AddCommand(newRemoveToppingCommand(clientFlags))
+	cmd.AddCommand(newMenuCommand(clientFlags))
+	return cmd
+}
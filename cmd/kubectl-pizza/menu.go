@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/cli"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type menuOptions struct {
+	ClientFlags *cli.ClientFlags
+	Output      string
+}
+
+func newMenuCommand(clientFlags *cli.ClientFlags) *cobra.Command {
+	o := &menuOptions{ClientFlags: clientFlags}
+	cmd := &cobra.Command{
+		Use:   "menu",
+		Short: "Print the topping catalog",
+		Long: `Menu prints the Toppings with their prices in alphabetical order. Wide output
+adds the lifecycle phase, the stock and the units of it reserved, and the
+number of pizzas in all namespaces using each topping.`,
+		Example: `  kubectl pizza menu -o wide`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(cmd.Context(), os.Stdout)
+		},
+	}
+	addOutputFlag(cmd.Flags(), &o.Output)
+	return cmd
+}
+
+func (o *menuOptions) Run(ctx context.Context, out io.Writer) error {
+	if err := validateOutput(o.Output); err != nil {
+		return err
+	}
+	config, err := o.ClientFlags.RESTConfig()
+	if err != nil {
+		return err
+	}
+	clientset, err := versioned.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	c, err := loadCatalog(ctx, clientset)
+	if err != nil {
+		return err
+	}
+
+	names := c.names()
+	list := &v1alpha1.ToppingList{}
+	list.SetGroupVersionKind(toppingListKind)
+	for _, name := range names {
+		topping := c[name].DeepCopy()
+		topping.SetGroupVersionKind(toppingKind)
+		list.Items = append(list.Items, *topping)
+	}
+	if ok, err := printStructured(out, o.Output, list); ok {
+		return err
+	}
+
+	if len(names) == 0 {
+		fmt.Fprintln(os.Stderr, "The menu is empty.")
+		return nil
+	}
+	var usedBy map[string]int
+	if o.Output == outputWide {
+		pizzas, err := clientset.RestaurantV1beta1().Pizzas(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list pizzas: %w", err)
+		}
+		usedBy = map[string]int{}
+		for i := range pizzas.Items {
+			toppings := sets.New[string]()
+			for _, t := range pizzas.Items[i].AllToppings() {
+				toppings.Insert(t.Name)
+			}
+			for name := range toppings {
+				usedBy[name]++
+			}
+		}
+	}
+
+	w := newTabWriter(out)
+	columns := []string{"NAME", "PRICE", "AGE"}
+	if o.Output == outputWide {
+		columns = append(columns, "PHASE", "STOCK", "RESERVED", "PIZZAS")
+	}
+	fmt.Fprintln(w, strings.Join(columns, "\t"))
+	for _, name := range names {
+		topping := c[name]
+		row := []string{name, formatCost(topping.Spec.Cost), age(topping.CreationTimestamp)}
+		if o.Output == outputWide {
+			stock := "<unlimited>"
+			if topping.Spec.Stock != nil {
+				stock = fmt.Sprint(*topping.Spec.Stock)
+			}
+			row = append(row, string(topping.EffectivePhase(time.Now())), stock, fmt.Sprint(topping.Status.Reserved), fmt.Sprint(usedBy[name]))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
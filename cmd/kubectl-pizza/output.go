@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/yaml"
+)
+
+// Output formats of -o.
+const (
+	outputWide = "wide"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+var (
+	pizzaKind       = v1beta1.SchemeGroupVersion.WithKind("Pizza")
+	pizzaListKind   = v1beta1.SchemeGroupVersion.WithKind("PizzaList")
+	toppingKind     = v1alpha1.SchemeGroupVersion.WithKind("Topping")
+	toppingListKind = v1alpha1.SchemeGroupVersion.WithKind("ToppingList")
+)
+
+func addOutputFlag(fs *pflag.FlagSet, output *string) {
+	fs.StringVarP(output, "output", "o", *output, "Output format. One of: json, yaml, wide.")
+}
+
+func validateOutput(output string) error {
+	switch output {
+	case "", outputWide, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q, must be one of json, yaml, wide", output)
+	}
+}
+
+// printStructured writes obj as JSON or YAML. It returns false for the table
+// formats.
+func printStructured(out io.Writer, output string, obj interface{}) (bool, error) {
+	switch output {
+	case outputJSON:
+		bs, err := json.MarshalIndent(obj, "", "    ")
+		if err != nil {
+			return true, err
+		}
+		_, err = fmt.Fprintf(out, "%s\n", bs)
+		return true, err
+	case outputYAML:
+		bs, err := yaml.Marshal(obj)
+		if err != nil {
+			return true, err
+		}
+		_, err = out.Write(bs)
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func newTabWriter(out io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+}
+
+// age formats the time since t like kubectl get.
+func age(t metav1.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(t.Time))
+}
+
+// formatCost formats a price with two decimals.
+func formatCost(cost float64) string {
+	return fmt.Sprintf("%.2f", cost)
+}
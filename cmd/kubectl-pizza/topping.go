@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/cli"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+type toppingOptions struct {
+	ClientFlags *cli.ClientFlags
+	Quantity    int
+	Output      string
+}
+
+func newAddToppingCommand(clientFlags *cli.ClientFlags) *cobra.Command {
+	o := &toppingOptions{ClientFlags: clientFlags, Quantity: 1}
+	cmd := &cobra.Command{
+		Use:   "add-topping PIZZA TOPPING",
+		Short: "Add a topping to a pizza",
+		Long: `Add-topping puts a topping from the menu onto a Pizza. If the pizza has the
+topping already, its quantity is increased. Concurrent changes of the pizza
+are retried.`,
+		Example: `  # Double the salami
+  kubectl pizza add-topping margherita salami --quantity 2`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(cmd.Context(), args[0], args[1], addTopping, os.Stdout)
+		},
+	}
+	cmd.Flags().IntVar(&o.Quantity, "quantity", o.Quantity, "The number of pieces to add.")
+	addOutputFlag(cmd.Flags(), &o.Output)
+	return cmd
+}
+
+func newRemoveToppingCommand(clientFlags *cli.ClientFlags) *cobra.Command {
+	o := &toppingOptions{ClientFlags: clientFlags}
+	cmd := &cobra.Command{
+		Use:   "remove-topping PIZZA TOPPING",
+		Short: "Remove a topping from a pizza",
+		Long: `Remove-topping takes a topping off a Pizza, all pieces of it unless --quantity
+is given. The last topping cannot be removed, because a pizza without toppings
+gets the default toppings. Concurrent changes of the pizza are retried.`,
+		Example: `  # No more pineapple
+  kubectl pizza remove-topping hawaii pineapple`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(cmd.Context(), args[0], args[1], removeTopping, os.Stdout)
+		},
+	}
+	cmd.Flags().IntVar(&o.Quantity, "quantity", o.Quantity, "The number of pieces to remove. 0 removes all.")
+	addOutputFlag(cmd.Flags(), &o.Output)
+	return cmd
+}
+
+// toppingChange changes the toppings of a pizza in place.
+type toppingChange func(pizza *v1beta1.Pizza, topping string, quantity int) error
+
+func addTopping(pizza *v1beta1.Pizza, topping string, quantity int) error {
+	for i := range pizza.Spec.Toppings {
+		if pizza.Spec.Toppings[i].Name == topping {
+			pizza.Spec.Toppings[i].Quantity += quantity
+			return nil
+		}
+	}
+	pizza.Spec.Toppings = append(pizza.Spec.Toppings, v1beta1.PizzaTopping{Name: topping, Quantity: quantity})
+	return nil
+}
+
+func removeTopping(pizza *v1beta1.Pizza, topping string, quantity int) error {
+	for i := range pizza.Spec.Toppings {
+		t := &pizza.Spec.Toppings[i]
+		if t.Name != topping {
+			continue
+		}
+		if quantity > 0 && quantity < t.Quantity {
+			t.Quantity -= quantity
+			return nil
+		}
+		if len(pizza.Spec.Toppings) == 1 {
+			return fmt.Errorf("cannot remove %s, the last topping of pizza %s", topping, pizza.Name)
+		}
+		pizza.Spec.Toppings = append(pizza.Spec.Toppings[:i], pizza.Spec.Toppings[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("pizza %s has no %s", pizza.Name, topping)
+}
+
+func (o *toppingOptions) Run(ctx context.Context, pizzaName, toppingName string, change toppingChange, out io.Writer) error {
+	if err := validateOutput(o.Output); err != nil {
+		return err
+	}
+	if o.Quantity < 0 {
+		return fmt.Errorf("the quantity must not be negative, got %d", o.Quantity)
+	}
+	namespace, err := o.ClientFlags.Namespace()
+	if err != nil {
+		return err
+	}
+	config, err := o.ClientFlags.RESTConfig()
+	if err != nil {
+		return err
+	}
+	clientset, err := versioned.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if _, err := clientset.RestaurantV1alpha1().Toppings().Get(ctx, toppingName, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+		return fmt.Errorf("%s is not on the menu, see kubectl pizza menu", toppingName)
+	} else if err != nil {
+		return err
+	}
+
+	client := clientset.RestaurantV1beta1().Pizzas(namespace)
+	var updated *v1beta1.Pizza
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pizza, err := client.Get(ctx, pizzaName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if err := change(pizza, toppingName, o.Quantity); err != nil {
+			return err
+		}
+		updated, err = client.Update(ctx, pizza, metav1.UpdateOptions{})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	updated.SetGroupVersionKind(pizzaKind)
+	if ok, err := printStructured(out, o.Output, updated); ok {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "pizza/%s updated: %s\n", updated.Name, formatToppings(updated.Spec.Toppings))
+	return err
+}
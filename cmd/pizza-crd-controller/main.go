@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/zeroisme/pizza-crd/pkg/cli"
+	"github.com/zeroisme/pizza-crd/pkg/controller/cost"
+	"github.com/zeroisme/pizza-crd/pkg/controller/lifecycle"
+	"github.com/zeroisme/pizza-crd/pkg/controller/order"
+	"github.com/zeroisme/pizza-crd/pkg/controller/recipe"
+	"github.com/zeroisme/pizza-crd/pkg/controller/stock"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	restaurantinformers "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/rest"
+	"k8s.io/component-base/cli/globalflag"
+	componentbaseconfig "k8s.io/component-base/config"
+	"k8s.io/component-base/featuregate"
+	"k8s.io/component-base/logs"
+	logsapi "k8s.io/component-base/logs/api/v1"
+	_ "k8s.io/component-base/logs/json/register"
+	"k8s.io/klog/v2"
+)
+
+func NewDefaultOptions() *Options {
+	o := &Options{
+		ClientConnection: componentbaseconfig.ClientConnectionConfiguration{
+			ContentType: "application/json",
+			QPS:         20,
+			Burst:       30,
+		},
+		Workers:      2,
+		ResyncPeriod: 10 * time.Minute,
+		Logs:         logs.NewOptions(),
+		FeatureGate:  featuregate.NewFeatureGate(),
+	}
+	utilruntime.Must(logsapi.AddFeatureGates(o.FeatureGate))
+	utilruntime.Must(o.FeatureGate.SetFromMap(map[string]bool{string(logsapi.ContextualLogging): true}))
+	return o
+}
+
+type Options struct {
+	// ClientConnection configures the connection to the Kubernetes API server.
+	ClientConnection componentbaseconfig.ClientConnectionConfiguration
+	// Master overrides the API server address from the kubeconfig.
+	Master string
+	// Context selects a context from the kubeconfig other than the current one.
+	Context string
+
+	// Workers is the number of objects each controller processes concurrently.
+	Workers int
+	// ResyncPeriod is the interval all objects are processed again.
+	ResyncPeriod time.Duration
+
+	Logs        *logs.Options
+	FeatureGate featuregate.MutableFeatureGate
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.ClientConnection.Kubeconfig, "kubeconfig", o.ClientConnection.Kubeconfig,
+		"Path to a kubeconfig file. If unset, the KUBECONFIG environment variable, ~/.kube/config and the in-cluster configuration are tried in this order.")
+	fs.StringVar(&o.Master, "master", o.Master,
+		"The address of the Kubernetes API server. Overrides any value in the kubeconfig.")
+	fs.StringVar(&o.Context, "context", o.Context,
+		"The name of the kubeconfig context to use. Defaults to the current context.")
+	fs.StringVar(&o.ClientConnection.ContentType, "kube-api-content-type", o.ClientConnection.ContentType,
+		"Content type of requests sent to the API server.")
+	fs.Float32Var(&o.ClientConnection.QPS, "kube-api-qps", o.ClientConnection.QPS,
+		"QPS to use while talking with the Kubernetes API server.")
+	fs.Int32Var(&o.ClientConnection.Burst, "kube-api-burst", o.ClientConnection.Burst,
+		"Burst to use while talking with the Kubernetes API server.")
+
+	fs.IntVar(&o.Workers, "workers", o.Workers,
+		"The number of objects each controller processes concurrently.")
+	fs.DurationVar(&o.ResyncPeriod, "resync-period", o.ResyncPeriod,
+		"The interval in which all objects are processed again. Zero disables resyncs.")
+
+	logsapi.AddFlags(o.Logs, fs)
+	o.FeatureGate.AddFlag(fs)
+}
+
+func (o *Options) Validate() error {
+	var errs []error
+	if o.ClientConnection.QPS < 0 {
+		errs = append(errs, fmt.Errorf("--kube-api-qps must not be negative, got %v", o.ClientConnection.QPS))
+	}
+	if o.ClientConnection.Burst < 0 {
+		errs = append(errs, fmt.Errorf("--kube-api-burst must not be negative, got %d", o.ClientConnection.Burst))
+	}
+	if o.Workers <= 0 {
+		errs = append(errs, fmt.Errorf("--workers must be positive, got %d", o.Workers))
+	}
+	if o.ResyncPeriod < 0 {
+		errs = append(errs, fmt.Errorf("--resync-period must not be negative, got %v", o.ResyncPeriod))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// restConfig loads the client configuration from the kubeconfig flags and
+// applies the client connection settings.
+func (o *Options) restConfig() (*rest.Config, error) {
+	config, err := cli.LoadClientConfig(o.ClientConnection.Kubeconfig, o.Master, o.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	config.ContentType = o.ClientConnection.ContentType
+	config.AcceptContentTypes = o.ClientConnection.AcceptContentTypes
+	config.QPS = o.ClientConnection.QPS
+	config.Burst = int(o.ClientConnection.Burst)
+	config.UserAgent = rest.DefaultKubernetesUserAgent()
+
+	return config, nil
+}
+
+func main() {
+	opt := NewDefaultOptions()
+	fs := pflag.NewFlagSet("pizza-crd-controller", pflag.ExitOnError)
+	globalflag.AddGlobalFlags(fs, "pizza-crd-controller", logs.SkipLoggingConfigurationFlags())
+	opt.AddFlags(fs)
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := run(opt); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(opt *Options) error {
+	if err := logsapi.ValidateAndApply(opt.Logs, opt.FeatureGate); err != nil {
+		return fmt.Errorf("invalid logging options: %w", err)
+	}
+	defer logs.FlushLogs()
+
+	if err := opt.Validate(); err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	config, err := opt.restConfig()
+	if err != nil {
+		return err
+	}
+	clientset, err := versioned.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create restaurant clientset for %s: %w", config.Host, err)
+	}
+
+	ctx := server.SetupSignalContext()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	restaurantInformers := restaurantinformers.NewSharedInformerFactory(clientset, opt.ResyncPeriod)
+	costController, err := cost.NewController(clientset, restaurantInformers, cost.ControllerName)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", cost.ControllerName, err)
+	}
+	orderController, err := order.NewController(clientset, restaurantInformers, order.ControllerName)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", order.ControllerName, err)
+	}
+	stockController, err := stock.NewController(clientset, restaurantInformers, stock.ControllerName)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", stock.ControllerName, err)
+	}
+	lifecycleController, err := lifecycle.NewController(clientset, restaurantInformers, lifecycle.ControllerName)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", lifecycle.ControllerName, err)
+	}
+	recipeController, err := recipe.NewController(clientset, restaurantInformers, recipe.ControllerName)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", recipe.ControllerName, err)
+	}
+	restaurantInformers.Start(ctx.Done())
+
+	var wg sync.WaitGroup
+	for _, c := range []interface {
+		Run(ctx context.Context, workers int)
+	}{costController, orderController, stockController, lifecycleController, recipeController} {
+		c := c
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Run(ctx, opt.Workers)
+		}()
+	}
+
+	<-ctx.Done()
+	klog.InfoS("Shutting down, waiting for controllers to stop")
+	wg.Wait()
+	restaurantInformers.Shutdown()
+	klog.InfoS("Shutdown complete")
+	return nil
+}
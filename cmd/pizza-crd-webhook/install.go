@@ -0,0 +1,523 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	deployment "github.com/zeroisme/pizza-crd/manifests/deployment-v1"
+	"github.com/zeroisme/pizza-crd/pkg/cli"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/util/cert"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultNamespace      = "pizza-crd"
+	serviceName           = "webhook"
+	servingCertName       = "serving-cert"
+	webhookDeploymentName = "pizza-crd-webhook"
+
+	// certRenewBefore is how long before it expires an existing serving
+	// certificate is replaced on install.
+	certRenewBefore = 30 * 24 * time.Hour
+)
+
+var (
+	crdGVR        = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	secretGVR     = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	deploymentGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+)
+
+// InstallOptions configure the install and uninstall subcommands.
+type InstallOptions struct {
+	Kubeconfig string
+	Master     string
+	Context    string
+
+	// Namespace is the namespace the webhook is deployed to.
+	Namespace string
+	// Image overrides the webhook image of the Deployment.
+	Image string
+	// FieldManager is the field manager of server-side apply.
+	FieldManager string
+	// Timeout bounds waiting for the CRDs to become established and for the
+	// webhook Deployment to become available.
+	Timeout time.Duration
+
+	// DryRun lists the objects instead of applying or deleting them.
+	DryRun bool
+	// Output prints the objects in the given format instead of applying them.
+	Output string
+	// DeleteCRDs makes uninstall delete the CRDs and with them all pizzas and toppings.
+	DeleteCRDs bool
+}
+
+func NewDefaultInstallOptions() *InstallOptions {
+	return &InstallOptions{
+		Namespace:    defaultNamespace,
+		FieldManager: "pizza-crd-webhook",
+		Timeout:      time.Minute,
+	}
+}
+
+func (o *InstallOptions) AddFlags(fs *pflag.FlagSet, install bool) {
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig,
+		"Path to a kubeconfig file. If unset, the KUBECONFIG environment variable, ~/.kube/config and the in-cluster configuration are tried in this order.")
+	fs.StringVar(&o.Master, "master", o.Master,
+		"The address of the Kubernetes API server. Overrides any value in the kubeconfig.")
+	fs.StringVar(&o.Context, "context", o.Context,
+		"The name of the kubeconfig context to use. Defaults to the current context.")
+	fs.StringVarP(&o.Namespace, "namespace", "n", o.Namespace,
+		"The namespace of the webhook.")
+	fs.BoolVar(&o.DryRun, "dry-run", o.DryRun,
+		"Only print the objects that would be changed, without contacting the cluster.")
+
+	if install {
+		fs.StringVar(&o.Image, "image", o.Image,
+			"The webhook image. Defaults to the image in the bundled Deployment.")
+		fs.StringVar(&o.FieldManager, "field-manager", o.FieldManager,
+			"The field manager used for server-side apply.")
+		fs.DurationVar(&o.Timeout, "timeout", o.Timeout,
+			"The time to wait for the CustomResourceDefinitions to become established and for the webhook Deployment to become available.")
+		fs.StringVarP(&o.Output, "output", "o", o.Output,
+			"Print the manifests in this format instead of applying them. Only yaml is supported.")
+	} else {
+		fs.BoolVar(&o.DeleteCRDs, "delete-crds", o.DeleteCRDs,
+			"Also delete the CustomResourceDefinitions, and with them all Pizzas and Toppings.")
+	}
+}
+
+func (o *InstallOptions) Validate() error {
+	if len(o.Namespace) == 0 {
+		return fmt.Errorf("--namespace must not be empty")
+	}
+	if len(o.Output) > 0 && o.Output != "yaml" {
+		return fmt.Errorf("unsupported --output %q, only yaml is supported", o.Output)
+	}
+	if o.Timeout <= 0 {
+		return fmt.Errorf("--timeout must be positive, got %v", o.Timeout)
+	}
+	return nil
+}
+
+// runInstaller runs the install or uninstall subcommand.
+func runInstaller(command string, args []string) error {
+	opt := NewDefaultInstallOptions()
+	fs := pflag.NewFlagSet("pizza-crd-webhook "+command, pflag.ExitOnError)
+	opt.AddFlags(fs, command == "install")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+	if err := opt.Validate(); err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	if len(opt.Output) > 0 || opt.DryRun {
+		objs, err := opt.render(nil, nil)
+		if err != nil {
+			return err
+		}
+		if len(opt.Output) > 0 {
+			return printManifests(os.Stdout, objs)
+		}
+		verb := "serverside-applied"
+		if command == "uninstall" {
+			verb = "deleted"
+		}
+		for _, obj := range opt.selected(command, objs) {
+			fmt.Printf("%s %s (dry run)\n", objectRef(obj), verb)
+		}
+		return nil
+	}
+
+	ctx := server.SetupSignalContext()
+	client, mapper, err := opt.clients()
+	if err != nil {
+		return err
+	}
+	var certPEM, keyPEM []byte
+	if command == "install" {
+		// keep the certificate the running webhook serves and the API server
+		// trusts, so that reinstalling does not break admission until the
+		// webhook pods picked up a new one
+		if certPEM, keyPEM, err = opt.existingServingCert(ctx, client); err != nil {
+			return err
+		}
+	}
+	objs, err := opt.render(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+	if command == "uninstall" {
+		return opt.uninstall(ctx, client, mapper, objs)
+	}
+	return opt.install(ctx, client, mapper, objs)
+}
+
+// existingServingCert returns the certificate and key of the serving secret
+// of an earlier install, or nil if there is none or it is about to expire.
+func (o *InstallOptions) existingServingCert(ctx context.Context, client dynamic.Interface) ([]byte, []byte, error) {
+	secret, err := client.Resource(secretGVR).Namespace(o.Namespace).Get(ctx, servingCertName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to get secret %s/%s: %w", o.Namespace, servingCertName, err)
+	}
+	data, _, _ := unstructured.NestedStringMap(secret.Object, "data")
+	certPEM, _ := base64.StdEncoding.DecodeString(data["tls.crt"])
+	keyPEM, _ := base64.StdEncoding.DecodeString(data["tls.key"])
+	notAfter, err := o.verifyServingCert(certPEM, keyPEM)
+	if err != nil {
+		fmt.Printf("secret/%s has no usable serving certificate, generating a new one: %v\n", servingCertName, err)
+		return nil, nil, nil
+	}
+	fmt.Printf("secret/%s has a serving certificate valid until %s, reusing it\n", servingCertName, notAfter.UTC().Format(time.RFC3339))
+	return certPEM, keyPEM, nil
+}
+
+// verifyServingCert checks that certPEM and keyPEM are a key pair for the
+// webhook Service which is valid for at least certRenewBefore, and returns
+// when the certificate expires.
+func (o *InstallOptions) verifyServingCert(certPEM, keyPEM []byte) (time.Time, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return time.Time{}, err
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := leaf.VerifyHostname(o.serviceHost()); err != nil {
+		return time.Time{}, err
+	}
+	if time.Now().Add(certRenewBefore).After(leaf.NotAfter) {
+		return time.Time{}, fmt.Errorf("expires at %s", leaf.NotAfter.UTC().Format(time.RFC3339))
+	}
+	return leaf.NotAfter, nil
+}
+
+// serviceHost returns the host name of the webhook Service.
+func (o *InstallOptions) serviceHost() string {
+	return fmt.Sprintf("%s.%s.svc", serviceName, o.Namespace)
+}
+
+// render reads the bundled manifests and fills in the namespace, the image
+// and the serving certificate. Without certPEM and keyPEM, a new self-signed
+// certificate is generated.
+func (o *InstallOptions) render(certPEM, keyPEM []byte) ([]*unstructured.Unstructured, error) {
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		host := o.serviceHost()
+		var err error
+		certPEM, keyPEM, err = cert.GenerateSelfSignedCertKey(host, nil, []string{serviceName + "." + o.Namespace, host + ".cluster.local"})
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate serving certificate: %w", err)
+		}
+	}
+	// the certificate is followed by the CA which signed it
+	caBundle := base64.StdEncoding.EncodeToString(certPEM)
+
+	var objs []*unstructured.Unstructured
+	for _, name := range deployment.Files {
+		data, err := deployment.FS.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+		for {
+			obj := &unstructured.Unstructured{}
+			if err := decoder.Decode(&obj.Object); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %w", name, err)
+			}
+			if len(obj.Object) == 0 {
+				continue
+			}
+			if err := o.customize(obj, caBundle, certPEM, keyPEM); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			objs = append(objs, obj)
+		}
+	}
+	return objs, nil
+}
+
+func (o *InstallOptions) customize(obj *unstructured.Unstructured, caBundle string, certPEM, keyPEM []byte) error {
+	if len(obj.GetNamespace()) > 0 {
+		obj.SetNamespace(o.Namespace)
+	}
+
+	switch obj.GetKind() {
+	case "Namespace":
+		obj.SetName(o.Namespace)
+	case "CustomResourceDefinition":
+		if _, found, _ := unstructured.NestedMap(obj.Object, "spec", "conversion", "webhook", "clientConfig"); found {
+			if err := setClientConfig(obj.Object, caBundle, o.Namespace, "spec", "conversion", "webhook", "clientConfig"); err != nil {
+				return err
+			}
+		}
+	case "MutatingWebhookConfiguration", "ValidatingWebhookConfiguration":
+		webhooks, _, err := unstructured.NestedSlice(obj.Object, "webhooks")
+		if err != nil {
+			return err
+		}
+		for _, w := range webhooks {
+			if err := setClientConfig(w.(map[string]interface{}), caBundle, o.Namespace, "clientConfig"); err != nil {
+				return err
+			}
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, webhooks, "webhooks"); err != nil {
+			return err
+		}
+	case "ClusterRoleBinding":
+		subjects, _, err := unstructured.NestedSlice(obj.Object, "subjects")
+		if err != nil {
+			return err
+		}
+		for _, s := range subjects {
+			if subject := s.(map[string]interface{}); subject["kind"] == "ServiceAccount" {
+				subject["namespace"] = o.Namespace
+			}
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, subjects, "subjects"); err != nil {
+			return err
+		}
+	case "Secret":
+		if obj.GetName() == servingCertName {
+			if err := unstructured.SetNestedStringMap(obj.Object, map[string]string{
+				"tls.crt": base64.StdEncoding.EncodeToString(certPEM),
+				"tls.key": base64.StdEncoding.EncodeToString(keyPEM),
+			}, "data"); err != nil {
+				return err
+			}
+		}
+	case "Deployment":
+		if len(o.Image) > 0 {
+			containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+			if err != nil {
+				return err
+			}
+			for _, c := range containers {
+				c.(map[string]interface{})["image"] = o.Image
+			}
+			if err := unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func setClientConfig(obj map[string]interface{}, caBundle, namespace string, fields ...string) error {
+	if err := unstructured.SetNestedField(obj, caBundle, append(fields, "caBundle")...); err != nil {
+		return err
+	}
+	if _, found, _ := unstructured.NestedMap(obj, append(fields, "service")...); found {
+		return unstructured.SetNestedField(obj, namespace, append(fields, "service", "namespace")...)
+	}
+	return nil
+}
+
+// selected returns the objects the command changes. Uninstall deletes in
+// reverse order and keeps the CRDs unless asked otherwise.
+func (o *InstallOptions) selected(command string, objs []*unstructured.Unstructured) []*unstructured.Unstructured {
+	if command == "install" {
+		return objs
+	}
+	var result []*unstructured.Unstructured
+	for i := len(objs) - 1; i >= 0; i-- {
+		if objs[i].GetKind() == "CustomResourceDefinition" && !o.DeleteCRDs {
+			continue
+		}
+		result = append(result, objs[i])
+	}
+	return result
+}
+
+// install applies objs in order. It waits for the CRDs to become established
+// before applying anything else, and for the webhook Deployment to become
+// available before applying the webhook configurations, which would otherwise
+// reject all requests they match until it is.
+func (o *InstallOptions) install(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper, objs []*unstructured.Unstructured) error {
+	var crds []string
+	webhookAvailable := false
+	for _, obj := range objs {
+		if obj.GetKind() != "CustomResourceDefinition" && len(crds) > 0 {
+			if err := o.waitForEstablished(ctx, client, crds); err != nil {
+				return err
+			}
+			crds = nil
+		}
+		if kind := obj.GetKind(); (kind == "MutatingWebhookConfiguration" || kind == "ValidatingWebhookConfiguration") && !webhookAvailable {
+			if err := o.waitForAvailable(ctx, client, webhookDeploymentName); err != nil {
+				return err
+			}
+			webhookAvailable = true
+		}
+
+		ri, err := resourceFor(client, mapper, obj)
+		if err != nil {
+			return err
+		}
+		if _, err := ri.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: o.FieldManager, Force: true}); err != nil {
+			return fmt.Errorf("failed to apply %s: %w", objectRef(obj), err)
+		}
+		fmt.Printf("%s serverside-applied\n", objectRef(obj))
+
+		if obj.GetKind() == "CustomResourceDefinition" {
+			crds = append(crds, obj.GetName())
+		}
+	}
+	if len(crds) > 0 {
+		return o.waitForEstablished(ctx, client, crds)
+	}
+	return nil
+}
+
+func (o *InstallOptions) uninstall(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper, objs []*unstructured.Unstructured) error {
+	propagation := metav1.DeletePropagationBackground
+	for _, obj := range o.selected("uninstall", objs) {
+		ri, err := resourceFor(client, mapper, obj)
+		if err != nil {
+			return err
+		}
+		err = ri.Delete(ctx, obj.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if errors.IsNotFound(err) {
+			fmt.Printf("%s not found\n", objectRef(obj))
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to delete %s: %w", objectRef(obj), err)
+		}
+		fmt.Printf("%s deleted\n", objectRef(obj))
+	}
+	return nil
+}
+
+func (o *InstallOptions) clients() (dynamic.Interface, meta.RESTMapper, error) {
+	config, err := cli.LoadClientConfig(o.Kubeconfig, o.Master, o.Context)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create client for %s: %w", config.Host, err)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create discovery client for %s: %w", config.Host, err)
+	}
+	return client, restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)), nil
+}
+
+func resourceFor(client dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find resource of %s: %w", objectRef(obj), err)
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return client.Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+	}
+	return client.Resource(mapping.Resource), nil
+}
+
+func (o *InstallOptions) waitForEstablished(ctx context.Context, client dynamic.Interface, names []string) error {
+	for _, name := range names {
+		err := wait.PollUntilContextTimeout(ctx, time.Second, o.Timeout, true, func(ctx context.Context) (bool, error) {
+			crd, err := client.Resource(crdGVR).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+			for _, c := range conditions {
+				if condition, ok := c.(map[string]interface{}); ok && condition["type"] == "Established" && condition["status"] == "True" {
+					return true, nil
+				}
+			}
+			return false, nil
+		})
+		if err != nil {
+			return fmt.Errorf("CustomResourceDefinition %s not established: %w", name, err)
+		}
+		fmt.Printf("customresourcedefinition.apiextensions.k8s.io/%s established\n", name)
+	}
+	return nil
+}
+
+// waitForAvailable waits until the rollout of a Deployment is complete, i.e.
+// all its replicas are updated and available, like kubectl rollout status.
+func (o *InstallOptions) waitForAvailable(ctx context.Context, client dynamic.Interface, name string) error {
+	err := wait.PollUntilContextTimeout(ctx, time.Second, o.Timeout, true, func(ctx context.Context) (bool, error) {
+		deployment, err := client.Resource(deploymentGVR).Namespace(o.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		observedGeneration, _, _ := unstructured.NestedInt64(deployment.Object, "status", "observedGeneration")
+		if observedGeneration < deployment.GetGeneration() {
+			return false, nil
+		}
+		replicas, found, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		statusReplicas, _, _ := unstructured.NestedInt64(deployment.Object, "status", "replicas")
+		updated, _, _ := unstructured.NestedInt64(deployment.Object, "status", "updatedReplicas")
+		available, _, _ := unstructured.NestedInt64(deployment.Object, "status", "availableReplicas")
+		return updated >= replicas && statusReplicas <= updated && available >= updated, nil
+	})
+	if err != nil {
+		return fmt.Errorf("Deployment %s/%s not available: %w", o.Namespace, name, err)
+	}
+	fmt.Printf("deployment.apps/%s available\n", name)
+	return nil
+}
+
+func printManifests(w io.Writer, objs []*unstructured.Unstructured) error {
+	for i, obj := range objs {
+		bs, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(bs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// objectRef formats an object the way kubectl does, e.g. deployment.apps/name.
+func objectRef(obj *unstructured.Unstructured) string {
+	kind := strings.ToLower(obj.GetKind())
+	if group := obj.GroupVersionKind().Group; len(group) > 0 {
+		kind += "." + group
+	}
+	return kind + "/" + obj.GetName()
+}
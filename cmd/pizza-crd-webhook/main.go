@@ -1,110 +1,411 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"os"
-	"path/filepath"
+	"sync/atomic"
 	"time"
 
-	"github.com/gorilla/handlers"
-
 	"github.com/spf13/pflag"
+	"github.com/zeroisme/pizza-crd/pkg/cli"
 	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
-	"github.com/zeroisme/pizza-crd/pkg/webhook/admission"
-	"github.com/zeroisme/pizza-crd/pkg/webhook/conversion"
+	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	"github.com/zeroisme/pizza-crd/pkg/webhook/record"
+	webhookserver "github.com/zeroisme/pizza-crd/pkg/webhook/server"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericapifilters "k8s.io/apiserver/pkg/endpoints/filters"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/server"
+	genericfilters "k8s.io/apiserver/pkg/server/filters"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/apiserver/pkg/server/httplog"
 	"k8s.io/apiserver/pkg/server/options"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/component-base/cli/globalflag"
+	componentbaseconfig "k8s.io/component-base/config"
+	"k8s.io/component-base/featuregate"
+	"k8s.io/component-base/logs"
+	logsapi "k8s.io/component-base/logs/api/v1"
+	_ "k8s.io/component-base/logs/json/register"
+	"k8s.io/component-base/tracing"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+	"k8s.io/klog/v2"
 
 	restaurantinformers "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions"
 )
 
 func NewDefaultOptions() *Options {
 	o := &Options{
-		*options.NewSecureServingOptions(),
+		SecureServing: *options.NewSecureServingOptions(),
+		ClientConnection: componentbaseconfig.ClientConnectionConfiguration{
+			ContentType: "application/json",
+			QPS:         50,
+			Burst:       100,
+		},
+		MaxRequestsInFlight: 400,
+		RequestTimeout:      30 * time.Second,
+		MaxRequestBodyBytes: 16 * 1024 * 1024,
+		ShutdownDelay:       10 * time.Second,
+		ShutdownTimeout:     30 * time.Second,
+		RecordMaxFiles:      1000,
+		RecordSampleRate:    1,
+		RecordRedact:        true,
+		Logs:                logs.NewOptions(),
+		FeatureGate:         featuregate.NewFeatureGate(),
 	}
+	utilruntime.Must(logsapi.AddFeatureGates(o.FeatureGate))
+	// the handlers log through the request context
+	utilruntime.Must(o.FeatureGate.SetFromMap(map[string]bool{string(logsapi.ContextualLogging): true}))
 	o.SecureServing.ServerCert.PairName = "pizza-crd-webhook"
 	return o
 }
 
 type Options struct {
 	SecureServing options.SecureServingOptions
+
+	// ClientConnection configures the connection to the Kubernetes API server.
+	ClientConnection componentbaseconfig.ClientConnectionConfiguration
+	// Master overrides the API server address from the kubeconfig.
+	Master string
+	// Context selects a context from the kubeconfig other than the current one.
+	Context string
+
+	// MaxRequestsInFlight limits the number of reviews served concurrently.
+	MaxRequestsInFlight int
+	// RequestTimeout is the upper bound for the deadline of a single review.
+	RequestTimeout time.Duration
+	// MaxRequestBodyBytes limits the size of a review.
+	MaxRequestBodyBytes int64
+	// ShutdownDelay is the time between failing readiness and closing the listener.
+	ShutdownDelay time.Duration
+	// ShutdownTimeout is the time in-flight reviews get to finish once the listener is closed.
+	ShutdownTimeout time.Duration
+
+	// RecordDir is the directory reviews are recorded to. Recording is disabled if empty.
+	RecordDir string
+	// RecordMaxFiles is the number of recorded reviews kept.
+	RecordMaxFiles int
+	// RecordSampleRate is the fraction of reviews recorded.
+	RecordSampleRate float64
+	// RecordRedact removes user information and managed fields from recorded reviews.
+	RecordRedact bool
+
+	Logs        *logs.Options
+	FeatureGate featuregate.MutableFeatureGate
+
+	// TracingEndpoint is the OTLP gRPC collector address. Tracing is disabled if empty.
+	TracingEndpoint string
+	// TracingSamplingRatePerMillion is the number of reviews sampled per million
+	// unless the API server decided about sampling already.
+	TracingSamplingRatePerMillion int32
 }
 
 type Config struct {
 	SecureServing *server.SecureServingInfo
+	ClientConfig  *rest.Config
+
+	MaxRequestsInFlight int
+	RequestTimeout      time.Duration
+	MaxRequestBodyBytes int64
+	ShutdownDelay       time.Duration
+	ShutdownTimeout     time.Duration
+
+	// Tracing is nil if tracing is disabled.
+	Tracing *tracingapi.TracingConfiguration
+	// Recorder is nil if recording is disabled.
+	Recorder *record.Recorder
 }
 
 func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	o.SecureServing.AddFlags(fs)
+
+	fs.StringVar(&o.ClientConnection.Kubeconfig, "kubeconfig", o.ClientConnection.Kubeconfig,
+		"Path to a kubeconfig file. If unset, the KUBECONFIG environment variable, ~/.kube/config and the in-cluster configuration are tried in this order.")
+	fs.StringVar(&o.Master, "master", o.Master,
+		"The address of the Kubernetes API server. Overrides any value in the kubeconfig.")
+	fs.StringVar(&o.Context, "context", o.Context,
+		"The name of the kubeconfig context to use. Defaults to the current context.")
+	fs.StringVar(&o.ClientConnection.ContentType, "kube-api-content-type", o.ClientConnection.ContentType,
+		"Content type of requests sent to the API server.")
+	fs.Float32Var(&o.ClientConnection.QPS, "kube-api-qps", o.ClientConnection.QPS,
+		"QPS to use while talking with the Kubernetes API server.")
+	fs.Int32Var(&o.ClientConnection.Burst, "kube-api-burst", o.ClientConnection.Burst,
+		"Burst to use while talking with the Kubernetes API server.")
+
+	fs.IntVar(&o.MaxRequestsInFlight, "max-requests-inflight", o.MaxRequestsInFlight,
+		"The maximum number of reviews served concurrently. Further reviews are rejected with 429 Too Many Requests. Zero for no limit.")
+	fs.DurationVar(&o.RequestTimeout, "request-timeout", o.RequestTimeout,
+		"The maximum duration of a review. Shorter timeouts requested by the API server take precedence.")
+	fs.Int64Var(&o.MaxRequestBodyBytes, "max-request-body-bytes", o.MaxRequestBodyBytes,
+		"The maximum size of a review body in bytes. Larger reviews are rejected with 413 Request Entity Too Large. Zero for no limit.")
+
+	fs.DurationVar(&o.ShutdownDelay, "shutdown-delay-duration", o.ShutdownDelay,
+		"Time to keep serving after a termination signal while /readyz fails, so that the webhook Service endpoints are removed before the listener closes.")
+	fs.DurationVar(&o.ShutdownTimeout, "shutdown-timeout", o.ShutdownTimeout,
+		"Time in-flight reviews get to finish after the listener is closed. Reviews still running afterwards are dropped.")
+
+	fs.StringVar(&o.TracingEndpoint, "tracing-endpoint", o.TracingEndpoint,
+		"The OpenTelemetry collector endpoint (OTLP over gRPC, host:port) to export traces to. Tracing is disabled if empty.")
+	fs.Int32Var(&o.TracingSamplingRatePerMillion, "tracing-sampling-rate-per-million", o.TracingSamplingRatePerMillion,
+		"The number of reviews per million to trace. Reviews sent by an API server which traces the request are always traced.")
+
+	fs.StringVar(&o.RecordDir, "record-dir", o.RecordDir,
+		"Directory to record reviews and responses to for replay with pizzactl replay. Recording is disabled if empty.")
+	fs.IntVar(&o.RecordMaxFiles, "record-max-files", o.RecordMaxFiles,
+		"The number of recorded reviews to keep. The oldest records are deleted first.")
+	fs.Float64Var(&o.RecordSampleRate, "record-sample-rate", o.RecordSampleRate,
+		"The fraction of reviews to record, between 0 and 1.")
+	fs.BoolVar(&o.RecordRedact, "record-redact", o.RecordRedact,
+		"Remove user information, managed fields and last-applied configurations from recorded reviews.")
+
+	logsapi.AddFlags(o.Logs, fs)
+	o.FeatureGate.AddFlag(fs)
+}
+
+func (o *Options) Validate() error {
+	errs := o.SecureServing.Validate()
+	if o.ClientConnection.QPS < 0 {
+		errs = append(errs, fmt.Errorf("--kube-api-qps must not be negative, got %v", o.ClientConnection.QPS))
+	}
+	if o.ClientConnection.Burst < 0 {
+		errs = append(errs, fmt.Errorf("--kube-api-burst must not be negative, got %d", o.ClientConnection.Burst))
+	}
+	if o.MaxRequestsInFlight < 0 {
+		errs = append(errs, fmt.Errorf("--max-requests-inflight must not be negative, got %d", o.MaxRequestsInFlight))
+	}
+	if o.RequestTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("--request-timeout must be positive, got %v", o.RequestTimeout))
+	}
+	if o.MaxRequestBodyBytes < 0 {
+		errs = append(errs, fmt.Errorf("--max-request-body-bytes must not be negative, got %d", o.MaxRequestBodyBytes))
+	}
+	if o.ShutdownDelay < 0 {
+		errs = append(errs, fmt.Errorf("--shutdown-delay-duration must not be negative, got %v", o.ShutdownDelay))
+	}
+	if o.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("--shutdown-timeout must be positive, got %v", o.ShutdownTimeout))
+	}
+	if o.RecordMaxFiles <= 0 {
+		errs = append(errs, fmt.Errorf("--record-max-files must be positive, got %d", o.RecordMaxFiles))
+	}
+	if o.RecordSampleRate < 0 || o.RecordSampleRate > 1 {
+		errs = append(errs, fmt.Errorf("--record-sample-rate must be between 0 and 1, got %v", o.RecordSampleRate))
+	}
+	if tracing := o.tracingConfiguration(); tracing != nil {
+		errs = append(errs, tracingapi.ValidateTracingConfiguration(tracing, o.FeatureGate, field.NewPath("tracing")).ToAggregate())
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (o *Options) tracingConfiguration() *tracingapi.TracingConfiguration {
+	if len(o.TracingEndpoint) == 0 {
+		return nil
+	}
+	return &tracingapi.TracingConfiguration{
+		Endpoint:               &o.TracingEndpoint,
+		SamplingRatePerMillion: &o.TracingSamplingRatePerMillion,
+	}
 }
 
 func (o *Options) Config() (*Config, error) {
 	if err := o.SecureServing.MaybeDefaultWithSelfSignedCerts("0.0.0.0", nil, nil); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to create self-signed serving certificate: %w", err)
 	}
 
-	c := &Config{}
+	c := &Config{
+		MaxRequestsInFlight: o.MaxRequestsInFlight,
+		RequestTimeout:      o.RequestTimeout,
+		MaxRequestBodyBytes: o.MaxRequestBodyBytes,
+		ShutdownDelay:       o.ShutdownDelay,
+		ShutdownTimeout:     o.ShutdownTimeout,
+		Tracing:             o.tracingConfiguration(),
+	}
 
 	if err := o.SecureServing.ApplyTo(&c.SecureServing); err != nil {
+		return nil, fmt.Errorf("failed to apply secure serving options: %w", err)
+	}
+
+	if len(o.RecordDir) > 0 {
+		recorder, err := record.New(record.Options{
+			Dir:        o.RecordDir,
+			MaxFiles:   o.RecordMaxFiles,
+			SampleRate: o.RecordSampleRate,
+			Redact:     o.RecordRedact,
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.Recorder = recorder
+	}
+
+	clientConfig, err := o.restConfig()
+	if err != nil {
 		return nil, err
 	}
+	c.ClientConfig = clientConfig
 
 	return c, nil
 }
 
+// restConfig loads the client configuration from the kubeconfig flags and
+// applies the client connection settings.
+func (o *Options) restConfig() (*rest.Config, error) {
+	config, err := cli.LoadClientConfig(o.ClientConnection.Kubeconfig, o.Master, o.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	config.ContentType = o.ClientConnection.ContentType
+	config.AcceptContentTypes = o.ClientConnection.AcceptContentTypes
+	config.QPS = o.ClientConnection.QPS
+	config.Burst = int(o.ClientConnection.Burst)
+	config.UserAgent = rest.DefaultKubernetesUserAgent()
+
+	return config, nil
+}
+
 func main() {
+	if len(os.Args) > 1 && (os.Args[1] == "install" || os.Args[1] == "uninstall") {
+		if err := runInstaller(os.Args[1], os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	opt := NewDefaultOptions()
 	fs := pflag.NewFlagSet("pizza-crd-webhook", pflag.ExitOnError)
-	globalflag.AddGlobalFlags(fs, "pizza-crd-webhook")
+	globalflag.AddGlobalFlags(fs, "pizza-crd-webhook", logs.SkipLoggingConfigurationFlags())
 	opt.AddFlags(fs)
-	if err := fs.Parse(os.Args); err != nil {
-		panic(err)
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := run(opt); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(opt *Options) error {
+	if err := logsapi.ValidateAndApply(opt.Logs, opt.FeatureGate); err != nil {
+		return fmt.Errorf("invalid logging options: %w", err)
+	}
+	defer logs.FlushLogs()
+
+	if err := opt.Validate(); err != nil {
+		return fmt.Errorf("invalid options: %w", err)
 	}
 
 	cfg, err := opt.Config()
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	config, err := rest.InClusterConfig()
+	tracerProvider, err := tracing.NewProvider(context.Background(), cfg.Tracing, nil, []resource.Option{
+		resource.WithAttributes(semconv.ServiceNameKey.String("pizza-crd-webhook")),
+	})
 	if err != nil {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			panic(err)
-		}
-		kubeconfig := filepath.Join(home, ".kube", "config")
-		if envvar := os.Getenv("KUBECONFIG"); len(envvar) > 0 {
-			kubeconfig = envvar
-		}
-		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
-		if err != nil {
-			panic(err)
-		}
+		return fmt.Errorf("failed to set up tracing: %w", err)
 	}
-	clientset, err := versioned.NewForConfig(config)
+	defer func() {
+		if err := tracerProvider.Shutdown(context.Background()); err != nil {
+			klog.ErrorS(err, "Failed to flush traces")
+		}
+	}()
+	cfg.ClientConfig.Wrap(tracing.WrapperFor(tracerProvider))
+
+	clientset, err := versioned.NewForConfig(cfg.ClientConfig)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("failed to create restaurant clientset for %s: %w", cfg.ClientConfig.Host, err)
 	}
 
-	stopCh := server.SetupSignalHandler()
+	ctx := server.SetupSignalContext()
+	serverStopCh := make(chan struct{})
+	informerStopCh := make(chan struct{})
+	var shuttingDown atomic.Bool
+	var inFlight webhook.InFlight
 
 	// register handlers
 
 	restaurantInformers := restaurantinformers.NewSharedInformerFactory(clientset, time.Second*30)
 	mux := http.NewServeMux()
-	mux.Handle("/convert/v1beta1/pizza", http.HandlerFunc(conversion.Serve))
-	mux.Handle("/admit/v1beta1/pizza", http.HandlerFunc(admission.ServePizzaAdmit))
-	mux.Handle("/validate/v1beta1/pizza", http.HandlerFunc(admission.ServePizzaValidation(restaurantInformers)))
-	restaurantInformers.Start(stopCh)
+	webhookserver.InstallHandlers(mux, restaurantInformers)
+	// the probes bypass the filters, so that the kubelet does not restart or
+	// unready the webhook because the in-flight limit is reached
+	root := http.NewServeMux()
+	root.Handle("/", buildHandlerChain(mux, cfg, tracerProvider, &inFlight))
+	healthz.InstallHandler(root, healthz.PingHealthz)
+	healthz.InstallReadyzHandler(root,
+		healthz.NewInformerSyncHealthz(restaurantInformers),
+		healthz.NamedCheck("shutdown", func(*http.Request) error {
+			if shuttingDown.Load() {
+				return fmt.Errorf("shutting down")
+			}
+			return nil
+		}),
+	)
+	restaurantInformers.Start(informerStopCh)
 
 	// run server
-	if stoppedCh, listenerStoppedCh, err := cfg.SecureServing.Serve(handlers.LoggingHandler(os.Stdout, mux), time.Second*30, stopCh); err != nil {
-		panic(err)
+	stoppedCh, listenerStoppedCh, err := cfg.SecureServing.Serve(root, cfg.ShutdownTimeout, serverStopCh)
+	if err != nil {
+		return fmt.Errorf("failed to start secure server: %w", err)
+	}
+
+	// Shut down in order: fail readiness so that the endpoints are removed,
+	// drain in-flight reviews, then stop the informers they depend on.
+	<-ctx.Done()
+	shuttingDown.Store(true)
+	klog.InfoS("Shutting down, failing readiness", "delay", cfg.ShutdownDelay, "inFlight", inFlight.Count())
+	time.Sleep(cfg.ShutdownDelay)
+
+	klog.InfoS("Closing listener and draining in-flight reviews", "inFlight", inFlight.Count(), "timeout", cfg.ShutdownTimeout)
+	close(serverStopCh)
+	<-stoppedCh
+	if dropped := inFlight.Count(); dropped > 0 {
+		klog.InfoS("Dropped in-flight reviews after shutdown timeout", "dropped", dropped, "timeout", cfg.ShutdownTimeout)
 	} else {
-		<-stoppedCh
-		<-listenerStoppedCh
+		klog.InfoS("Drained all in-flight reviews")
 	}
+	<-listenerStoppedCh
+
+	close(informerStopCh)
+	restaurantInformers.Shutdown()
+	klog.InfoS("Shutdown complete")
+	return nil
+}
+
+// buildHandlerChain wraps the webhook handlers with the request filters, the
+// outermost filter last.
+func buildHandlerChain(handler http.Handler, c *Config, tp oteltrace.TracerProvider, inFlight *webhook.InFlight) http.Handler {
+	if c.Recorder != nil {
+		handler = c.Recorder.WithRecording(handler)
+	}
+	handler = inFlight.WithTracking(handler)
+	handler = genericfilters.WithTimeoutForNonLongRunningRequests(handler, neverLongRunning)
+	handler = webhook.WithRequestDeadline(handler, c.RequestTimeout)
+	handler = webhook.WithMaxRequestBodyBytes(handler, c.MaxRequestBodyBytes)
+	// reviews are POST requests and hence count as mutating
+	handler = genericfilters.WithMaxInFlightLimit(handler, 0, c.MaxRequestsInFlight, nil)
+	// spans are children of the API server's span if it sent a traceparent header
+	handler = tracing.WithTracing(handler, tp, "pizza-crd-webhook")
+	handler = genericapifilters.WithRequestInfo(handler, &apirequest.RequestInfoFactory{
+		APIPrefixes:          sets.NewString("api", "apis"),
+		GrouplessAPIPrefixes: sets.NewString("api"),
+	})
+	return httplog.WithLogging(handler, httplog.DefaultStacktracePred)
+}
+
+func neverLongRunning(*http.Request, *apirequest.RequestInfo) bool {
+	return false
 }
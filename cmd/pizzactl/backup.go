@@ -0,0 +1,293 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	"github.com/zeroisme/pizza-crd/pkg/cli"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	"github.com/zeroisme/pizza-crd/pkg/webhook/conversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// archiveFormatVersion is the version of the backup archive layout.
+	// Version 2 added the recipes, version 3 the promotions.
+	archiveFormatVersion = 3
+
+	archiveIndexName    = "index.json"
+	archiveToppingDir   = "toppings"
+	archivePromotionDir = "promotions"
+	archiveRecipeDir    = "pizzarecipes"
+	archivePizzaDir     = "pizzas"
+)
+
+// archiveIndex is the first entry of a backup archive.
+type archiveIndex struct {
+	// FormatVersion is the version of the archive layout.
+	FormatVersion int `json:"formatVersion"`
+	// CreatedAt is the time the backup was taken.
+	CreatedAt metav1.Time `json:"createdAt"`
+	// PizzaAPIVersion is the version the Pizzas are serialized in.
+	PizzaAPIVersion string `json:"pizzaAPIVersion"`
+	Toppings        int    `json:"toppings"`
+	Promotions      int    `json:"promotions,omitempty"`
+	Recipes         int    `json:"recipes,omitempty"`
+	Pizzas          int    `json:"pizzas"`
+}
+
+// archiveContents are the objects of a backup archive.
+type archiveContents struct {
+	Toppings   []runtime.Object
+	Promotions []runtime.Object
+	Recipes    []runtime.Object
+	Pizzas     []runtime.Object
+}
+
+// archiveGroup is a directory of a backup archive with its objects.
+type archiveGroup struct {
+	dir  string
+	objs *[]runtime.Object
+}
+
+// groups returns the directories of the archive in the order the objects
+// have to be restored, so that the Pizzas referring to Toppings and
+// PizzaRecipes pass validation.
+func (c *archiveContents) groups() []archiveGroup {
+	return []archiveGroup{
+		{archiveToppingDir, &c.Toppings},
+		{archivePromotionDir, &c.Promotions},
+		{archiveRecipeDir, &c.Recipes},
+		{archivePizzaDir, &c.Pizzas},
+	}
+}
+
+type backupOptions struct {
+	ClientFlags   *cli.ClientFlags
+	Filename      string
+	OutputVersion string
+	PageSize      int64
+}
+
+func newBackupCommand() *cobra.Command {
+	o := &backupOptions{
+		ClientFlags:   cli.NewClientFlags(),
+		OutputVersion: "v1beta1",
+		PageSize:      500,
+	}
+	cmd := &cobra.Command{
+		Use:   "backup -f ARCHIVE",
+		Short: "Export all Toppings, Promotions, PizzaRecipes and Pizzas to an archive",
+		Long: `Backup writes all Toppings and Promotions and the PizzaRecipes and Pizzas of all
+namespaces to a gzipped tar archive. Pizzas are converted to the given API version with the webhook
+conversion. Server-managed metadata like resourceVersion and uid is left out,
+so that the archive can be restored into any cluster with pizzactl restore.`,
+		Example: `  pizzactl backup -f restaurant-$(date +%F).tar.gz --output-version v1beta1`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(cmd.Context())
+		},
+	}
+	o.ClientFlags.AddFlags(cmd.Flags())
+	cmd.Flags().StringVarP(&o.Filename, "filename", "f", o.Filename, "The archive to write, - for standard output.")
+	cmd.Flags().StringVar(&o.OutputVersion, "output-version", o.OutputVersion, "The API version to serialize Pizzas in.")
+	cmd.Flags().Int64Var(&o.PageSize, "page-size", o.PageSize, "The number of objects listed per request.")
+	cmd.MarkFlagRequired("filename")
+	return cmd
+}
+
+func (o *backupOptions) Run(ctx context.Context) error {
+	gv, err := parseRestaurantVersion(o.OutputVersion)
+	if err != nil {
+		return err
+	}
+	config, err := o.ClientFlags.RESTConfig()
+	if err != nil {
+		return err
+	}
+	clientset, err := versioned.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	contents := &archiveContents{}
+	opts := metav1.ListOptions{Limit: o.PageSize}
+	for {
+		list, err := clientset.RestaurantV1alpha1().Toppings().List(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list toppings: %w", err)
+		}
+		for i := range list.Items {
+			topping := &list.Items[i]
+			topping.SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind("Topping"))
+			contents.Toppings = append(contents.Toppings, topping)
+		}
+		if opts.Continue = list.Continue; len(opts.Continue) == 0 {
+			break
+		}
+	}
+
+	opts = metav1.ListOptions{Limit: o.PageSize}
+	for {
+		list, err := clientset.RestaurantV1beta2().Promotions().List(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list promotions: %w", err)
+		}
+		for i := range list.Items {
+			promotion := &list.Items[i]
+			promotion.SetGroupVersionKind(v1beta2.SchemeGroupVersion.WithKind("Promotion"))
+			contents.Promotions = append(contents.Promotions, promotion)
+		}
+		if opts.Continue = list.Continue; len(opts.Continue) == 0 {
+			break
+		}
+	}
+
+	opts = metav1.ListOptions{Limit: o.PageSize}
+	for {
+		list, err := clientset.RestaurantV1beta2().PizzaRecipes(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list pizza recipes: %w", err)
+		}
+		for i := range list.Items {
+			recipe := &list.Items[i]
+			recipe.SetGroupVersionKind(v1beta2.SchemeGroupVersion.WithKind("PizzaRecipe"))
+			contents.Recipes = append(contents.Recipes, recipe)
+		}
+		if opts.Continue = list.Continue; len(opts.Continue) == 0 {
+			break
+		}
+	}
+
+	opts = metav1.ListOptions{Limit: o.PageSize}
+	for {
+		list, err := clientset.RestaurantV1alpha1().Pizzas(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list pizzas: %w", err)
+		}
+		for i := range list.Items {
+			var pizza runtime.Object = &list.Items[i]
+			pizza.GetObjectKind().SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind("Pizza"))
+			if gv != v1alpha1.SchemeGroupVersion {
+				if pizza, err = conversion.Convert(pizza, gv.String()); err != nil {
+					return fmt.Errorf("failed to convert pizza %s/%s: %w", list.Items[i].Namespace, list.Items[i].Name, err)
+				}
+			}
+			contents.Pizzas = append(contents.Pizzas, pizza)
+		}
+		if opts.Continue = list.Continue; len(opts.Continue) == 0 {
+			break
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if o.Filename != stdinName {
+		f, err := os.Create(o.Filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	index := archiveIndex{
+		FormatVersion:   archiveFormatVersion,
+		CreatedAt:       metav1.Now(),
+		PizzaAPIVersion: gv.String(),
+		Toppings:        len(contents.Toppings),
+		Promotions:      len(contents.Promotions),
+		Recipes:         len(contents.Recipes),
+		Pizzas:          len(contents.Pizzas),
+	}
+	if err := writeArchive(out, index, contents); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Backed up %d toppings, %d promotions, %d recipes and %d pizzas\n",
+		index.Toppings, index.Promotions, index.Recipes, index.Pizzas)
+	return nil
+}
+
+func writeArchive(w io.Writer, index archiveIndex, contents *archiveContents) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeArchiveFile(tw, archiveIndexName, data, index.CreatedAt.Time); err != nil {
+		return err
+	}
+	for _, group := range contents.groups() {
+		for _, obj := range *group.objs {
+			if err := stripServerMetadata(obj); err != nil {
+				return err
+			}
+			data, err := encodeDocument(obj, false)
+			if err != nil {
+				return err
+			}
+			if err := writeArchiveFile(tw, archiveObjectName(group.dir, obj), data, index.CreatedAt.Time); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeArchiveFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// archiveObjectName returns the archive path of an object, e.g.
+// pizzas/default/margherita.yaml.
+func archiveObjectName(dir string, obj runtime.Object) string {
+	m := obj.(metav1.Object)
+	return path.Join(dir, m.GetNamespace(), m.GetName()+".yaml")
+}
+
+// stripServerMetadata removes the metadata the API server sets, which must
+// not be sent on create.
+func stripServerMetadata(obj runtime.Object) error {
+	m, ok := obj.(metav1.Object)
+	if !ok {
+		return fmt.Errorf("unexpected object %T", obj)
+	}
+	m.SetUID("")
+	m.SetResourceVersion("")
+	m.SetGeneration(0)
+	m.SetCreationTimestamp(metav1.Time{})
+	m.SetDeletionTimestamp(nil)
+	m.SetDeletionGracePeriodSeconds(nil)
+	m.SetManagedFields(nil)
+	m.SetOwnerReferences(nil)
+	m.SetSelfLink("")
+	delete(m.GetAnnotations(), "kubectl.kubernetes.io/last-applied-configuration")
+	if len(m.GetAnnotations()) == 0 {
+		m.SetAnnotations(nil)
+	}
+	return nil
+}
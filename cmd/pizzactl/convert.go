@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	"github.com/zeroisme/pizza-crd/pkg/webhook/conversion"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+type convertOptions struct {
+	Filenames     []string
+	OutputVersion string
+	InPlace       bool
+}
+
+func newConvertCommand() *cobra.Command {
+	o := &convertOptions{}
+	cmd := &cobra.Command{
+		Use:   "convert -f FILENAME --output-version VERSION",
+		Short: "Convert Pizza manifests to another API version",
+		Long: `Convert rewrites the Pizza objects in YAML or JSON manifests to the given API
+version using the same conversion as the webhook. Other documents are copied
+unchanged. Files are read from standard input if no filename is given.`,
+		Example: `  # Migrate all manifests in a directory to v1beta1
+  pizzactl convert -f manifests/ --output-version v1beta1 --in-place`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+	cmd.Flags().StringSliceVarP(&o.Filenames, "filename", "f", o.Filenames, "Files or directories containing manifests, - for standard input.")
+	cmd.Flags().StringVar(&o.OutputVersion, "output-version", o.OutputVersion, "The API version to convert Pizzas to, e.g. v1beta1 or "+v1alpha1.GroupName+"/v1beta1.")
+	cmd.Flags().BoolVarP(&o.InPlace, "in-place", "i", o.InPlace, "Rewrite the files instead of printing the result.")
+	cmd.MarkFlagRequired("output-version")
+	return cmd
+}
+
+func (o *convertOptions) Run() error {
+	gv, err := parseRestaurantVersion(o.OutputVersion)
+	if err != nil {
+		return err
+	}
+	filenames, err := expandFilenames(o.Filenames)
+	if err != nil {
+		return err
+	}
+
+	for i, name := range filenames {
+		data, err := readFile(name)
+		if err != nil {
+			return err
+		}
+		converted, err := convertManifest(data, gv)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+
+		if o.InPlace && name != stdinName {
+			if !bytes.Equal(data, converted) {
+				if err := writeFile(name, converted); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if i > 0 {
+			fmt.Fprintln(os.Stdout, "---")
+		}
+		if _, err := os.Stdout.Write(converted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convertManifest converts all Pizzas in a manifest to gv.
+func convertManifest(data []byte, gv schema.GroupVersion) ([]byte, error) {
+	docs, err := splitDocuments(data)
+	if err != nil {
+		return nil, err
+	}
+	changed := false
+	for i, doc := range docs {
+		obj, ok, err := decodeDocument(doc)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %v", i, err)
+		}
+		if !ok || obj.GetObjectKind().GroupVersionKind().Kind != "Pizza" || obj.GetObjectKind().GroupVersionKind().GroupVersion() == gv {
+			continue
+		}
+		out, err := conversion.Convert(obj, gv.String())
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %v", i, err)
+		}
+		if docs[i], err = encodeDocument(out, utilyaml.IsJSONBuffer(doc)); err != nil {
+			return nil, fmt.Errorf("document %d: %v", i, err)
+		}
+		changed = true
+	}
+	if !changed {
+		return data, nil
+	}
+	if len(docs) == 1 && utilyaml.IsJSONBuffer(data) {
+		return docs[0], nil
+	}
+	return joinDocuments(docs), nil
+}
+
+// parseRestaurantVersion parses a version of the restaurant API group, with
+// or without the group name.
+func parseRestaurantVersion(version string) (schema.GroupVersion, error) {
+	if !strings.Contains(version, "/") {
+		version = v1alpha1.GroupName + "/" + version
+	}
+	gv, err := schema.ParseGroupVersion(version)
+	if err != nil {
+		return schema.GroupVersion{}, err
+	}
+	if !webhook.Scheme.IsVersionRegistered(gv) || gv.Group != v1alpha1.GroupName {
+		return schema.GroupVersion{}, fmt.Errorf("unknown API version %q", version)
+	}
+	return gv, nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "pizzactl",
+		Short:         "pizzactl works with restaurant.programming-kubernetes.info objects",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.AddCommand(newConvertCommand())
+	cmd.AddCommand(newValidateCommand())
+	cmd.AddCommand(newMigrateStorageCommand())
+	cmd.AddCommand(newBackupCommand())
+	cmd.AddCommand(newRestoreCommand())
+	cmd.AddCommand(newReplayCommand())
+	return cmd
+}
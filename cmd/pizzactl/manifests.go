@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// stdinName is the filename denoting standard input.
+const stdinName = "-"
+
+// expandFilenames replaces directories by the manifest files they contain.
+func expandFilenames(filenames []string) ([]string, error) {
+	if len(filenames) == 0 {
+		return []string{stdinName}, nil
+	}
+	var result []string
+	for _, name := range filenames {
+		if name == stdinName {
+			result = append(result, name)
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			result = append(result, name)
+			continue
+		}
+		err = filepath.WalkDir(name, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".yaml", ".yml", ".json":
+				if !d.IsDir() {
+					result = append(result, path)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// readFile reads a manifest file or, for "-", standard input.
+func readFile(name string) ([]byte, error) {
+	if name == stdinName {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(name)
+}
+
+// splitDocuments splits a YAML stream or a JSON document into documents.
+func splitDocuments(data []byte) ([][]byte, error) {
+	if utilyaml.IsJSONBuffer(data) {
+		return [][]byte{data}, nil
+	}
+	var docs [][]byte
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+}
+
+// joinDocuments joins documents to a YAML stream.
+func joinDocuments(docs [][]byte) []byte {
+	var buf bytes.Buffer
+	for i, doc := range docs {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(doc)
+		if len(doc) > 0 && doc[len(doc)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+// decodeDocument decodes a document into an object of the restaurant API
+// group. ok is false for documents of any other kind and for empty documents.
+func decodeDocument(doc []byte) (obj runtime.Object, ok bool, err error) {
+	if len(bytes.TrimSpace(doc)) == 0 {
+		return nil, false, nil
+	}
+	obj, _, err = webhook.Codecs.UniversalDeserializer().Decode(doc, nil, nil)
+	switch {
+	case runtime.IsNotRegisteredError(err), runtime.IsMissingKind(err), runtime.IsMissingVersion(err):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, err
+	}
+	return obj, true, nil
+}
+
+// encodeDocument encodes obj as YAML, or as indented JSON if asJSON is set.
+// Fields the API server fills in, like a null creationTimestamp or an empty
+// status, are left out to keep manifests minimal.
+func encodeDocument(obj runtime.Object, asJSON bool) ([]byte, error) {
+	bs, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var u map[string]interface{}
+	if err := json.Unmarshal(bs, &u); err != nil {
+		return nil, err
+	}
+	if metadata, ok := u["metadata"].(map[string]interface{}); ok {
+		if ts, ok := metadata["creationTimestamp"]; ok && ts == nil {
+			delete(metadata, "creationTimestamp")
+		}
+	}
+	if status, ok := u["status"].(map[string]interface{}); ok && len(status) == 0 {
+		delete(u, "status")
+	}
+
+	if asJSON {
+		bs, err := json.MarshalIndent(u, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(bs, '\n'), nil
+	}
+	return yaml.Marshal(u)
+}
+
+// writeFile replaces the content of name, keeping its permissions.
+func writeFile(name string, data []byte) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(name, data, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	"github.com/zeroisme/pizza-crd/pkg/cli"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+)
+
+// pizzaCRDName is the name of the Pizza CustomResourceDefinition.
+const pizzaCRDName = "pizzas." + v1alpha1.GroupName
+
+type migrateOptions struct {
+	ClientFlags    *cli.ClientFlags
+	CheckpointFile string
+	PageSize       int64
+}
+
+// migrationCheckpoint is the progress of a migration, saved after every page.
+type migrationCheckpoint struct {
+	// StorageVersion is the version the Pizzas are migrated to.
+	StorageVersion string `json:"storageVersion"`
+	// Continue is the continue token of the next page.
+	Continue string `json:"continue,omitempty"`
+	// Migrated is the number of Pizzas rewritten so far. It starts over with
+	// the migration when the continue token expired.
+	Migrated int `json:"migrated"`
+}
+
+func newMigrateStorageCommand() *cobra.Command {
+	o := &migrateOptions{
+		ClientFlags:    cli.NewClientFlags(),
+		CheckpointFile: ".pizzactl-migrate-storage.json",
+		PageSize:       100,
+	}
+	cmd := &cobra.Command{
+		Use:   "migrate-storage",
+		Short: "Rewrite all Pizzas in the current storage version",
+		Long: `Migrate-storage rewrites every Pizza with a no-op update of its status, so
+that the API server encodes it in the storage version of the CRD. Progress is saved to a
+checkpoint file after every page; an interrupted migration resumes from there.
+Once all Pizzas are rewritten, status.storedVersions of the CRD is pruned to
+the storage version, after which older versions can be retired.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(cmd.Context())
+		},
+	}
+	o.ClientFlags.AddFlags(cmd.Flags())
+	cmd.Flags().StringVar(&o.CheckpointFile, "checkpoint-file", o.CheckpointFile, "File to save progress to. Empty to not checkpoint.")
+	cmd.Flags().Int64Var(&o.PageSize, "page-size", o.PageSize, "The number of Pizzas listed per request.")
+	return cmd
+}
+
+func (o *migrateOptions) Run(ctx context.Context) error {
+	if o.PageSize <= 0 {
+		return fmt.Errorf("--page-size must be positive, got %d", o.PageSize)
+	}
+	config, err := o.ClientFlags.RESTConfig()
+	if err != nil {
+		return err
+	}
+	clientset, err := versioned.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	crdClient, err := apiextensionsclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	crd, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, pizzaCRDName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	storageVersion := ""
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			storageVersion = v.Name
+		}
+	}
+	pizzas, err := pizzaRewriter(clientset, storageVersion)
+	if err != nil {
+		return err
+	}
+
+	checkpoint, err := o.loadCheckpoint()
+	if err != nil {
+		return err
+	}
+	if checkpoint.StorageVersion != storageVersion {
+		checkpoint = &migrationCheckpoint{StorageVersion: storageVersion}
+	} else if len(checkpoint.Continue) > 0 {
+		fmt.Fprintf(os.Stderr, "Resuming migration to %s after %d pizzas\n", storageVersion, checkpoint.Migrated)
+	}
+
+	for {
+		names, next, err := pizzas.list(ctx, metav1.ListOptions{Limit: o.PageSize, Continue: checkpoint.Continue})
+		if apierrors.IsResourceExpired(err) {
+			// the continue token is too old, rewriting some pizzas twice is
+			// harmless, but counting them twice is not
+			fmt.Fprintf(os.Stderr, "Checkpoint expired, restarting the migration\n")
+			checkpoint.Continue = ""
+			checkpoint.Migrated = 0
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list pizzas: %w", err)
+		}
+
+		for _, name := range names {
+			err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				return pizzas.rewrite(ctx, name.Namespace, name.Name)
+			})
+			if apierrors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return fmt.Errorf("failed to migrate pizza %s: %w", name, err)
+			}
+			checkpoint.Migrated++
+		}
+
+		checkpoint.Continue = next
+		if err := o.saveCheckpoint(checkpoint); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Migrated %d pizzas to %s\n", checkpoint.Migrated, storageVersion)
+		if len(next) == 0 {
+			break
+		}
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		crd, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, pizzaCRDName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		crd.Status.StoredVersions = []string{storageVersion}
+		_, err = crdClient.ApiextensionsV1().CustomResourceDefinitions().UpdateStatus(ctx, crd, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prune stored versions of %s: %w", pizzaCRDName, err)
+	}
+	fmt.Fprintf(os.Stderr, "Pruned stored versions of %s to %s\n", pizzaCRDName, storageVersion)
+
+	if len(o.CheckpointFile) > 0 {
+		if err := os.Remove(o.CheckpointFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *migrateOptions) loadCheckpoint() (*migrationCheckpoint, error) {
+	checkpoint := &migrationCheckpoint{}
+	if len(o.CheckpointFile) == 0 {
+		return checkpoint, nil
+	}
+	data, err := os.ReadFile(o.CheckpointFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoint, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %s: %w", o.CheckpointFile, err)
+	}
+	return checkpoint, nil
+}
+
+func (o *migrateOptions) saveCheckpoint(checkpoint *migrationCheckpoint) error {
+	if len(o.CheckpointFile) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	// write and rename to never leave a truncated checkpoint behind
+	tmp := o.CheckpointFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, o.CheckpointFile)
+}
+
+type objectName struct {
+	Namespace, Name string
+}
+
+func (n objectName) String() string {
+	return n.Namespace + "/" + n.Name
+}
+
+// rewriter lists and rewrites Pizzas through the typed client of one version.
+type rewriter struct {
+	list    func(ctx context.Context, opts metav1.ListOptions) ([]objectName, string, error)
+	rewrite func(ctx context.Context, namespace, name string) error
+}
+
+// pizzaRewriter returns a rewriter using the client of the storage version.
+// Any served version would do, but the storage version is served for sure.
+func pizzaRewriter(clientset versioned.Interface, version string) (*rewriter, error) {
+	switch version {
+	case "v1alpha1":
+		client := clientset.RestaurantV1alpha1()
+		return newRewriter(func(namespace string) pizzaClient[*v1alpha1.Pizza, *v1alpha1.PizzaList] {
+			return client.Pizzas(namespace)
+		}), nil
+	case "v1beta1":
+		client := clientset.RestaurantV1beta1()
+		return newRewriter(func(namespace string) pizzaClient[*v1beta1.Pizza, *v1beta1.PizzaList] {
+			return client.Pizzas(namespace)
+		}), nil
+	case "v1beta2":
+		client := clientset.RestaurantV1beta2()
+		return newRewriter(func(namespace string) pizzaClient[*v1beta2.Pizza, *v1beta2.PizzaList] {
+			return client.Pizzas(namespace)
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage version %q of %s", version, pizzaCRDName)
+	}
+}
+
+// pizzaList is a typed PizzaList of any version.
+type pizzaList interface {
+	runtime.Object
+	GetContinue() string
+}
+
+// pizzaClient is the part of the typed Pizza client of one version, with
+// Pizzas of type P and lists of type L, which the migration needs.
+type pizzaClient[P runtime.Object, L pizzaList] interface {
+	List(ctx context.Context, opts metav1.ListOptions) (L, error)
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (P, error)
+	UpdateStatus(ctx context.Context, pizza P, opts metav1.UpdateOptions) (P, error)
+}
+
+// newRewriter returns a rewriter using the typed clients returned by pizzas
+// for a namespace.
+//
+// Pizzas are written back unchanged through their status subresource. The
+// API server encodes them in the storage version again, but neither the spec
+// nor the Pizza admission webhooks are involved, so Pizzas which would not be
+// admitted anymore, e.g. because a topping ran out of stock or was retired,
+// do not block the migration.
+func newRewriter[P runtime.Object, L pizzaList](pizzas func(namespace string) pizzaClient[P, L]) *rewriter {
+	return &rewriter{
+		list: func(ctx context.Context, opts metav1.ListOptions) ([]objectName, string, error) {
+			list, err := pizzas(metav1.NamespaceAll).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+			items, err := meta.ExtractList(list)
+			if err != nil {
+				return nil, "", err
+			}
+			names := make([]objectName, 0, len(items))
+			for _, item := range items {
+				accessor, err := meta.Accessor(item)
+				if err != nil {
+					return nil, "", err
+				}
+				names = append(names, objectName{accessor.GetNamespace(), accessor.GetName()})
+			}
+			return names, list.GetContinue(), nil
+		},
+		rewrite: func(ctx context.Context, namespace, name string) error {
+			pizza, err := pizzas(namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			_, err = pizzas(namespace).UpdateStatus(ctx, pizza, metav1.UpdateOptions{})
+			return err
+		},
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/cobra"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned/fake"
+	restaurantinformers "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions"
+	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	"github.com/zeroisme/pizza-crd/pkg/webhook/record"
+	webhookserver "github.com/zeroisme/pizza-crd/pkg/webhook/server"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type replayOptions struct {
+	ToppingFilenames []string
+}
+
+func newReplayCommand() *cobra.Command {
+	o := &replayOptions{}
+	cmd := &cobra.Command{
+		Use:   "replay RECORD|DIR...",
+		Short: "Replay recorded reviews through the current webhook handlers",
+		Long: `Replay sends reviews recorded by pizza-crd-webhook --record-dir through the
+handlers of this build and compares the responses with the recorded ones.
+Toppings, PizzaRecipes and Promotions are taken from the files given with
+--toppings instead of a cluster. Replay exits non-zero if any response differs.`,
+		Example: `  # Check a new build against the reviews recorded in production
+  pizzactl replay records/ --toppings toppings/`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(cmd.Context(), args, os.Stdout)
+		},
+	}
+	cmd.Flags().StringSliceVar(&o.ToppingFilenames, "toppings", o.ToppingFilenames, "Files or directories containing the Topping catalog, PizzaRecipes and Promotions.")
+	return cmd
+}
+
+func (o *replayOptions) Run(ctx context.Context, args []string, out io.Writer) error {
+	var filenames []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			filenames = append(filenames, arg)
+			continue
+		}
+		files, err := record.List(arg)
+		if err != nil {
+			return err
+		}
+		filenames = append(filenames, files...)
+	}
+
+	var catalog []runtime.Object
+	if len(o.ToppingFilenames) > 0 {
+		toppingFilenames, err := expandFilenames(o.ToppingFilenames)
+		if err != nil {
+			return err
+		}
+		for _, name := range toppingFilenames {
+			docs, err := readDecodedDocuments(name)
+			if err != nil {
+				return err
+			}
+			for _, doc := range docs {
+				switch doc.Object.(type) {
+				case *v1alpha1.Topping, *v1beta2.PizzaRecipe, *v1beta2.Promotion:
+					catalog = append(catalog, doc.Object)
+				}
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	informers := restaurantinformers.NewSharedInformerFactory(fake.NewSimpleClientset(catalog...), 0)
+	defer func() {
+		cancel()
+		informers.Shutdown()
+	}()
+	mux := http.NewServeMux()
+	webhookserver.InstallHandlers(mux, informers)
+	informers.Start(ctx.Done())
+	informers.WaitForCacheSync(ctx.Done())
+
+	differ := 0
+	for _, filename := range filenames {
+		rec, err := record.Load(filename)
+		if err != nil {
+			return err
+		}
+		diff, err := replay(ctx, mux, rec)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+		if len(diff) == 0 {
+			fmt.Fprintf(out, "ok    %s\n", filename)
+			continue
+		}
+		differ++
+		fmt.Fprintf(out, "DIFF  %s (-recorded +replayed):\n", filename)
+		for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+			fmt.Fprintf(out, "    %s\n", line)
+		}
+	}
+
+	if differ > 0 {
+		return fmt.Errorf("%d of %d responses differ", differ, len(filenames))
+	}
+	return nil
+}
+
+// replay sends a recorded review to handler and returns the difference
+// between the recorded and the new response, or an empty string.
+func replay(ctx context.Context, handler http.Handler, rec *record.Record) (string, error) {
+	req := httptest.NewRequest(http.MethodPost, rec.Path, bytes.NewReader(rec.Request)).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	if rw.Code != rec.ResponseCode {
+		return fmt.Sprintf("response code: %d != %d\n", rec.ResponseCode, rw.Code), nil
+	}
+	if rw.Code != http.StatusOK {
+		var recorded string
+		if err := json.Unmarshal(rec.Response, &recorded); err != nil {
+			return "", fmt.Errorf("invalid recorded error response: %w", err)
+		}
+		return cmp.Diff(strings.TrimSpace(recorded), strings.TrimSpace(rw.Body.String())), nil
+	}
+
+	var review struct {
+		Request struct {
+			Object json.RawMessage `json:"object"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal(rec.Request, &review); err != nil {
+		return "", fmt.Errorf("invalid recorded request: %w", err)
+	}
+	recorded, err := normalizeResponse(rec.Response, review.Request.Object)
+	if err != nil {
+		return "", fmt.Errorf("invalid recorded response: %w", err)
+	}
+	replayed, err := normalizeResponse(rw.Body.Bytes(), review.Request.Object)
+	if err != nil {
+		return "", fmt.Errorf("invalid response: %w", err)
+	}
+	return cmp.Diff(recorded, replayed), nil
+}
+
+// normalizeResponse unmarshals a review with the redactions of the recorder.
+// A JSON patch is replaced by the object it results in, because equivalent
+// patches can differ in the order of operations.
+func normalizeResponse(data []byte, object []byte) (map[string]interface{}, error) {
+	var review map[string]interface{}
+	if err := json.Unmarshal(data, &review); err != nil {
+		return nil, err
+	}
+	webhook.RemoveSensitiveFields(review)
+	if response, ok := review["response"].(map[string]interface{}); ok {
+		if patch, ok := response["patch"].(string); ok {
+			bs, err := base64.StdEncoding.DecodeString(patch)
+			if err != nil {
+				return nil, fmt.Errorf("invalid patch: %w", err)
+			}
+			ops, err := jsonpatch.DecodePatch(bs)
+			if err != nil {
+				return nil, fmt.Errorf("invalid patch: %w", err)
+			}
+			patched, err := ops.Apply(object)
+			if err != nil {
+				return nil, fmt.Errorf("failed to apply patch: %w", err)
+			}
+			var obj interface{}
+			if err := json.Unmarshal(patched, &obj); err != nil {
+				return nil, err
+			}
+			response["patch"] = obj
+		}
+	}
+	return review, nil
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	"github.com/zeroisme/pizza-crd/pkg/cli"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+)
+
+// Conflict modes of restore for objects which exist already.
+const (
+	conflictSkip      = "skip"
+	conflictOverwrite = "overwrite"
+	conflictFail      = "fail"
+)
+
+type restoreOptions struct {
+	ClientFlags *cli.ClientFlags
+	Filename    string
+	Conflict    string
+	DryRun      bool
+}
+
+func newRestoreCommand() *cobra.Command {
+	o := &restoreOptions{
+		ClientFlags: cli.NewClientFlags(),
+		Conflict:    conflictFail,
+	}
+	cmd := &cobra.Command{
+		Use:   "restore -f ARCHIVE",
+		Short: "Import Toppings, Promotions, PizzaRecipes and Pizzas from a backup archive",
+		Long: `Restore creates the Toppings, Promotions, PizzaRecipes and Pizzas of an archive
+written by pizzactl backup. Pizzas are created last, so that the Pizzas
+referring to Toppings and PizzaRecipes pass validation. Namespaces of the
+PizzaRecipes and Pizzas must exist.`,
+		Example: `  # Restore a backup, replacing objects which exist already
+  pizzactl restore -f restaurant.tar.gz --conflict overwrite`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(cmd.Context())
+		},
+	}
+	o.ClientFlags.AddFlags(cmd.Flags())
+	cmd.Flags().StringVarP(&o.Filename, "filename", "f", o.Filename, "The archive to read, - for standard input.")
+	cmd.Flags().StringVar(&o.Conflict, "conflict", o.Conflict, "What to do with objects which exist already. One of: skip, overwrite, fail.")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun, "Send the requests as server-side dry-run without persisting anything.")
+	cmd.MarkFlagRequired("filename")
+	return cmd
+}
+
+func (o *restoreOptions) Run(ctx context.Context) error {
+	switch o.Conflict {
+	case conflictSkip, conflictOverwrite, conflictFail:
+	default:
+		return fmt.Errorf("unknown conflict mode %q, must be one of skip, overwrite, fail", o.Conflict)
+	}
+
+	var in io.Reader = os.Stdin
+	if o.Filename != stdinName {
+		f, err := os.Open(o.Filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	index, contents, err := readArchive(in)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Restoring %d toppings, %d promotions, %d recipes and %d pizzas in %s from a backup of %s\n",
+		len(contents.Toppings), len(contents.Promotions), len(contents.Recipes), len(contents.Pizzas),
+		index.PizzaAPIVersion, index.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+
+	config, err := o.ClientFlags.RESTConfig()
+	if err != nil {
+		return err
+	}
+	clientset, err := versioned.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+	for _, group := range contents.groups() {
+		for _, obj := range *group.objs {
+			result, err := o.restore(ctx, clientset, obj)
+			if err != nil {
+				return err
+			}
+			counts[result]++
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d created, %d overwritten, %d skipped\n", counts["created"], counts["overwritten"], counts["skipped"])
+	return nil
+}
+
+// readArchive returns the objects of an archive, each kind sorted by name.
+func readArchive(r io.Reader) (*archiveIndex, *archiveContents, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	tr := tar.NewReader(gz)
+
+	var index *archiveIndex
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, err
+		}
+		if hdr.Name == archiveIndexName {
+			index = &archiveIndex{}
+			if err := json.Unmarshal(data, index); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s: %w", archiveIndexName, err)
+			}
+			continue
+		}
+		files[hdr.Name] = data
+	}
+	if index == nil {
+		return nil, nil, fmt.Errorf("no %s found, not a pizzactl backup", archiveIndexName)
+	}
+	if index.FormatVersion > archiveFormatVersion {
+		return nil, nil, fmt.Errorf("archive format version %d is newer than the supported version %d", index.FormatVersion, archiveFormatVersion)
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	contents := &archiveContents{}
+	groups := contents.groups()
+	for _, name := range names {
+		obj, ok, err := decodeDocument(files[name])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if !ok {
+			return nil, nil, fmt.Errorf("%s: not a restaurant object", name)
+		}
+		found := false
+		for _, group := range groups {
+			if strings.HasPrefix(name, group.dir+"/") {
+				*group.objs = append(*group.objs, obj)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, nil, fmt.Errorf("unexpected archive entry %s", name)
+		}
+	}
+	return index, contents, nil
+}
+
+// restore creates obj and handles conflicts according to the conflict mode.
+// It returns whether obj was created, overwritten or skipped.
+func (o *restoreOptions) restore(ctx context.Context, clientset versioned.Interface, obj runtime.Object) (string, error) {
+	if err := stripServerMetadata(obj); err != nil {
+		return "", err
+	}
+	var dryRun []string
+	if o.DryRun {
+		dryRun = []string{metav1.DryRunAll}
+	}
+	createOpts := metav1.CreateOptions{DryRun: dryRun}
+	updateOpts := metav1.UpdateOptions{DryRun: dryRun}
+
+	var create, overwrite func() error
+	var ref string
+	switch obj := obj.(type) {
+	case *v1alpha1.Topping:
+		ref = "topping/" + obj.Name
+		create, overwrite = writers[*v1alpha1.Topping](ctx, clientset.RestaurantV1alpha1().Toppings(), obj, createOpts, updateOpts)
+	case *v1beta2.Promotion:
+		ref = "promotion/" + obj.Name
+		create, overwrite = writers[*v1beta2.Promotion](ctx, clientset.RestaurantV1beta2().Promotions(), obj, createOpts, updateOpts)
+	case *v1beta2.PizzaRecipe:
+		ref = "pizzarecipe/" + obj.Namespace + "/" + obj.Name
+		create, overwrite = writers[*v1beta2.PizzaRecipe](ctx, clientset.RestaurantV1beta2().PizzaRecipes(obj.Namespace), obj, createOpts, updateOpts)
+	case *v1alpha1.Pizza:
+		ref = "pizza/" + obj.Namespace + "/" + obj.Name
+		create, overwrite = writers[*v1alpha1.Pizza](ctx, clientset.RestaurantV1alpha1().Pizzas(obj.Namespace), obj, createOpts, updateOpts)
+	case *v1beta1.Pizza:
+		ref = "pizza/" + obj.Namespace + "/" + obj.Name
+		create, overwrite = writers[*v1beta1.Pizza](ctx, clientset.RestaurantV1beta1().Pizzas(obj.Namespace), obj, createOpts, updateOpts)
+	case *v1beta2.Pizza:
+		ref = "pizza/" + obj.Namespace + "/" + obj.Name
+		create, overwrite = writers[*v1beta2.Pizza](ctx, clientset.RestaurantV1beta2().Pizzas(obj.Namespace), obj, createOpts, updateOpts)
+	default:
+		return "", fmt.Errorf("unexpected object %T in archive", obj)
+	}
+
+	err := create()
+	switch {
+	case err == nil:
+		fmt.Fprintf(os.Stderr, "%s created\n", ref)
+		return "created", nil
+	case !apierrors.IsAlreadyExists(err):
+		return "", fmt.Errorf("failed to create %s: %w", ref, err)
+	case o.Conflict == conflictSkip:
+		fmt.Fprintf(os.Stderr, "%s exists, skipped\n", ref)
+		return "skipped", nil
+	case o.Conflict == conflictOverwrite:
+		if err := retry.RetryOnConflict(retry.DefaultRetry, overwrite); err != nil {
+			return "", fmt.Errorf("failed to overwrite %s: %w", ref, err)
+		}
+		fmt.Fprintf(os.Stderr, "%s overwritten\n", ref)
+		return "overwritten", nil
+	default:
+		return "", fmt.Errorf("%s exists already, use --conflict to skip or overwrite it", ref)
+	}
+}
+
+// objectClient is the part of a typed client of objects of type T which
+// restore needs.
+type objectClient[T metav1.Object] interface {
+	Create(ctx context.Context, obj T, opts metav1.CreateOptions) (T, error)
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (T, error)
+	Update(ctx context.Context, obj T, opts metav1.UpdateOptions) (T, error)
+}
+
+// writers returns functions which create obj, and which overwrite the
+// existing object with obj.
+func writers[T metav1.Object](ctx context.Context, client objectClient[T], obj T, createOpts metav1.CreateOptions, updateOpts metav1.UpdateOptions) (create, overwrite func() error) {
+	create = func() error {
+		_, err := client.Create(ctx, obj, createOpts)
+		return err
+	}
+	overwrite = func() error {
+		existing, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		_, err = client.Update(ctx, obj, updateOpts)
+		return err
+	}
+	return create, overwrite
+}
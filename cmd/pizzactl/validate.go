@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1alpha1"
+	restaurantv1beta2 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1beta2"
+	"github.com/zeroisme/pizza-crd/pkg/webhook/admission"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/cache"
+)
+
+type validateOptions struct {
+	Filenames        []string
+	ToppingFilenames []string
+	Output           string
+}
+
+// validationResult is the outcome of validating one Pizza.
+type validationResult struct {
+	File       string            `json:"file"`
+	Document   int               `json:"document"`
+	APIVersion string            `json:"apiVersion"`
+	Namespace  string            `json:"namespace,omitempty"`
+	Name       string            `json:"name"`
+	Errors     []validationError `json:"errors,omitempty"`
+}
+
+type validationError struct {
+	Field  string `json:"field"`
+	Type   string `json:"type"`
+	Value  string `json:"value,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func (r validationResult) String() string {
+	if len(r.Namespace) > 0 {
+		return fmt.Sprintf("Pizza %s/%s", r.Namespace, r.Name)
+	}
+	return fmt.Sprintf("Pizza %s", r.Name)
+}
+
+func newValidateCommand() *cobra.Command {
+	o := &validateOptions{Output: "text"}
+	cmd := &cobra.Command{
+		Use:   "validate -f FILENAME [--toppings FILENAME]",
+		Short: "Validate Pizza manifests against a local topping catalog",
+		Long: `Validate defaults and validates the Pizza objects in YAML or JSON manifests
+the same way the admission webhooks do. Toppings and PizzaRecipes are taken
+from the files given with --toppings and from the validated manifests
+themselves. Validate exits non-zero if any Pizza is invalid.`,
+		Example: `  # Lint all pizzas in a directory and report to CI
+  pizzactl validate -f pizzas/ --toppings toppings/ -o junit > report.xml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(cmd.Context(), os.Stdout)
+		},
+	}
+	cmd.Flags().StringSliceVarP(&o.Filenames, "filename", "f", o.Filenames, "Files or directories containing manifests to validate, - for standard input.")
+	cmd.Flags().StringSliceVar(&o.ToppingFilenames, "toppings", o.ToppingFilenames, "Files or directories containing the Topping catalog and PizzaRecipes.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format. One of: text, json, junit.")
+	return cmd
+}
+
+func (o *validateOptions) Run(ctx context.Context, out io.Writer) error {
+	switch o.Output {
+	case "text", "json", "junit":
+	default:
+		return fmt.Errorf("unknown output format %q, must be one of text, json, junit", o.Output)
+	}
+	filenames, err := expandFilenames(o.Filenames)
+	if err != nil {
+		return err
+	}
+	var toppingFilenames []string
+	if len(o.ToppingFilenames) > 0 {
+		if toppingFilenames, err = expandFilenames(o.ToppingFilenames); err != nil {
+			return err
+		}
+	}
+
+	toppings := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	recipes := cache.NewIndexer(namespacedKey, cache.Indexers{})
+	var pizzas []decodedDocument
+	for _, name := range toppingFilenames {
+		docs, err := readDecodedDocuments(name)
+		if err != nil {
+			return err
+		}
+		if err := addToppings(toppings, docs); err != nil {
+			return err
+		}
+		if err := addRecipes(recipes, docs); err != nil {
+			return err
+		}
+	}
+	for _, name := range filenames {
+		docs, err := readDecodedDocuments(name)
+		if err != nil {
+			return err
+		}
+		if err := addToppings(toppings, docs); err != nil {
+			return err
+		}
+		if err := addRecipes(recipes, docs); err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			if doc.Object.GetObjectKind().GroupVersionKind().Kind == "Pizza" {
+				pizzas = append(pizzas, doc)
+			}
+		}
+	}
+
+	results, err := validatePizzas(ctx, pizzas, restaurantv1alpha1.NewToppingLister(toppings), restaurantv1beta2.NewPizzaRecipeLister(recipes))
+	if err != nil {
+		return err
+	}
+	switch o.Output {
+	case "json":
+		err = printJSON(out, results)
+	case "junit":
+		err = printJUnit(out, results)
+	default:
+		err = printText(out, results)
+	}
+	if err != nil {
+		return err
+	}
+
+	invalid := 0
+	for _, r := range results {
+		if len(r.Errors) > 0 {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d pizzas are invalid", invalid, len(results))
+	}
+	return nil
+}
+
+// decodedDocument is a restaurant object read from a manifest.
+type decodedDocument struct {
+	File     string
+	Document int
+	Object   runtime.Object
+}
+
+func readDecodedDocuments(name string) ([]decodedDocument, error) {
+	data, err := readFile(name)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := splitDocuments(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	var result []decodedDocument
+	for i, doc := range docs {
+		obj, ok, err := decodeDocument(doc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: document %d: %v", name, i, err)
+		}
+		if !ok {
+			continue
+		}
+		result = append(result, decodedDocument{File: name, Document: i, Object: obj})
+	}
+	return result, nil
+}
+
+// addToppings adds the Toppings among docs to the catalog.
+func addToppings(toppings cache.Indexer, docs []decodedDocument) error {
+	for _, doc := range docs {
+		if topping, ok := doc.Object.(*v1alpha1.Topping); ok {
+			if err := toppings.Add(topping); err != nil {
+				return fmt.Errorf("%s: document %d: %v", doc.File, doc.Document, err)
+			}
+		}
+	}
+	return nil
+}
+
+// addRecipes adds the PizzaRecipes among docs to the catalog.
+func addRecipes(recipes cache.Indexer, docs []decodedDocument) error {
+	for _, doc := range docs {
+		if recipe, ok := doc.Object.(*v1beta2.PizzaRecipe); ok {
+			if err := recipes.Add(recipe); err != nil {
+				return fmt.Errorf("%s: document %d: %v", doc.File, doc.Document, err)
+			}
+		}
+	}
+	return nil
+}
+
+// namespacedKey is the key a namespace lister looks objects up by, also for
+// manifests without a namespace.
+func namespacedKey(obj interface{}) (string, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", err
+	}
+	return accessor.GetNamespace() + "/" + accessor.GetName(), nil
+}
+
+// validatePizzas expands the recipes of pizzas, defaults and validates them
+// like the admission webhooks do on create.
+func validatePizzas(ctx context.Context, pizzas []decodedDocument, toppingLister restaurantv1alpha1.ToppingLister, recipeLister restaurantv1beta2.PizzaRecipeLister) ([]validationResult, error) {
+	results := make([]validationResult, 0, len(pizzas))
+	for _, doc := range pizzas {
+		accessor, err := meta.Accessor(doc.Object)
+		if err != nil {
+			return nil, fmt.Errorf("%s: document %d: %v", doc.File, doc.Document, err)
+		}
+		result := validationResult{
+			File:       doc.File,
+			Document:   doc.Document,
+			APIVersion: doc.Object.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+			Namespace:  accessor.GetNamespace(),
+			Name:       accessor.GetName(),
+		}
+
+		pizza := doc.Object.DeepCopyObject()
+		if err := admission.MutatePizza(ctx, pizza, nil, accessor.GetNamespace(), recipeLister); err != nil {
+			// the mutating webhook rejects the pizza, e.g. for a missing recipe
+			result.Errors = append(result.Errors, validationError{Field: "spec", Type: string(field.ErrorTypeInternal), Detail: err.Error()})
+			results = append(results, result)
+			continue
+		}
+		for _, err := range admission.ValidatePizza(ctx, pizza, toppingLister, recipeLister) {
+			e := validationError{Field: err.Field, Type: string(err.Type), Detail: err.Detail}
+			if err.BadValue != nil {
+				e.Value = fmt.Sprint(err.BadValue)
+			}
+			result.Errors = append(result.Errors, e)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (e validationError) String() string {
+	return (&field.Error{Type: field.ErrorType(e.Type), Field: e.Field, BadValue: e.Value, Detail: e.Detail}).Error()
+}
+
+func printText(out io.Writer, results []validationResult) error {
+	invalid := 0
+	for _, r := range results {
+		if len(r.Errors) == 0 {
+			continue
+		}
+		invalid++
+		for _, e := range r.Errors {
+			if _, err := fmt.Fprintf(out, "%s (document %d): %s: %s\n", r.File, r.Document, r, e); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(out, "%d pizzas checked, %d invalid\n", len(results), invalid)
+	return err
+}
+
+func printJSON(out io.Writer, results []validationResult) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func printJUnit(out io.Writer, results []validationResult) error {
+	suite := junitTestSuite{Name: "pizzactl validate", Tests: len(results)}
+	for _, r := range results {
+		tc := junitTestCase{Name: r.String(), ClassName: r.File}
+		if len(r.Errors) > 0 {
+			suite.Failures++
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d validation errors", len(r.Errors)),
+				Type:    "ValidationError",
+			}
+			for _, e := range r.Errors {
+				tc.Failure.Text += e.String() + "\n"
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, "\n")
+	return err
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const catalog = `apiVersion: restaurant.programming-kubernetes.info/v1alpha1
+kind: Topping
+metadata:
+  name: tomato
+spec:
+  cost: 0.5
+  vegan: true
+---
+apiVersion: restaurant.programming-kubernetes.info/v1alpha1
+kind: Topping
+metadata:
+  name: salami
+spec:
+  cost: 1
+---
+apiVersion: restaurant.programming-kubernetes.info/v1beta2
+kind: PizzaRecipe
+metadata:
+  name: marinara
+  namespace: default
+spec:
+  toppings:
+  - name: tomato
+    quantity: 1
+---
+apiVersion: restaurant.programming-kubernetes.info/v1beta2
+kind: PizzaRecipe
+metadata:
+  name: ghost-salami
+  namespace: default
+spec:
+  toppings:
+  - name: salami
+    quantity: 1
+  - name: ghost
+    quantity: 1
+`
+
+func TestValidateExpandedRecipe(t *testing.T) {
+	tests := []struct {
+		name    string
+		recipe  string
+		invalid bool
+		want    []string
+	}{
+		{
+			name:   "vegan recipe",
+			recipe: "marinara",
+			want:   []string{"1 pizzas checked, 0 invalid"},
+		},
+		{
+			name:    "recipe with missing and non-vegan toppings",
+			recipe:  "ghost-salami",
+			invalid: true,
+			want: []string{
+				`spec.toppings[1].name: Not found: "ghost"`,
+				`topping "salami" is not suitable`,
+				"1 pizzas checked, 1 invalid",
+			},
+		},
+		{
+			name:    "missing recipe",
+			recipe:  "margherita",
+			invalid: true,
+			want:    []string{`pizza recipe "margherita" not found`, "1 pizzas checked, 1 invalid"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			toppings := filepath.Join(dir, "catalog.yaml")
+			if err := os.WriteFile(toppings, []byte(catalog), 0644); err != nil {
+				t.Fatal(err)
+			}
+			pizza := filepath.Join(dir, "pizza.yaml")
+			if err := os.WriteFile(pizza, []byte(`apiVersion: restaurant.programming-kubernetes.info/v1beta1
+kind: Pizza
+metadata:
+  name: lunch
+  namespace: default
+spec:
+  dietaryClaims: [Vegan]
+  recipeRef:
+    name: `+test.recipe+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			o := &validateOptions{Filenames: []string{pizza}, ToppingFilenames: []string{toppings}, Output: "text"}
+			err := o.Run(context.Background(), &out)
+			if test.invalid != (err != nil) {
+				t.Errorf("expected an error %v, got %v", test.invalid, err)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("expected %q in the output:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
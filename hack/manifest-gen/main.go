@@ -0,0 +1,68 @@
+// manifest-gen generates the CustomResourceDefinitions and webhook
+// configurations in manifests/deployment-v1 from the Go types of the API.
+//
+// The OpenAPI schemas follow the types.go markers:
+//
+//	+optional, +required                  field is (not) required, overriding omitempty
+//	+genclient:nonNamespaced              the kind is cluster-scoped
+//	+kubebuilder:storageversion           the version is the storage version
+//	+kubebuilder:subresource:status       the version has a status subresource
+//	+listType=map, +listMapKey=type       the list is merged by key in server-side apply
+//	+listType=set                         the list items are unique
+//	+kubebuilder:validation:Minimum=1     and Maximum, MinLength, MaxLength, MinItems,
+//	                                      MaxItems, Pattern, Enum=a;b
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	apisDir := flag.String("apis-dir", "pkg/apis/restaurant", "The directory of the API group with one sub-directory per version.")
+	outputDir := flag.String("output-dir", "manifests/deployment-v1", "The directory to write the manifests to.")
+	verify := flag.Bool("verify", false, "Fail if the manifests in the output directory are stale instead of writing them.")
+	flag.Parse()
+
+	if err := run(*apisDir, *outputDir, *verify); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(apisDir, outputDir string, verify bool) error {
+	group, err := parseGroup(apisDir)
+	if err != nil {
+		return err
+	}
+	files, err := generate(group)
+	if err != nil {
+		return err
+	}
+
+	stale := 0
+	for _, name := range sortedNames(files) {
+		path := filepath.Join(outputDir, name)
+		if !verify {
+			if err := os.WriteFile(path, files[name], 0644); err != nil {
+				return err
+			}
+			continue
+		}
+		existing, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if !bytes.Equal(existing, files[name]) {
+			fmt.Fprintf(os.Stderr, "%s is out of date\n", path)
+			stale++
+		}
+	}
+	if stale > 0 {
+		return fmt.Errorf("%d manifests are out of date. Please run hack/update-manifests.sh", stale)
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	header = "# Code generated by manifest-gen. DO NOT EDIT.\n"
+
+	// caBundlePlaceholder is replaced by the CA certificate when deploying.
+	caBundlePlaceholder = "CERT"
+
+	serviceNamespace = "pizza-crd"
+	serviceName      = "webhook"
+)
+
+// kindWebhooks are the paths the webhook serves a kind on. Empty paths mean
+// there is no such webhook.
+type kindWebhooks struct {
+	Conversion string
+	Mutating   string
+	Validating string
+	// ValidateStatus sends updates of the status subresource to the
+	// validating webhook too.
+	ValidateStatus bool
+}
+
+// webhooks must match the mux of cmd/pizza-crd-webhook.
+var webhooks = map[string]kindWebhooks{
+	"Pizza": {
+		Conversion: webhook.ConvertPizzaPath,
+		Mutating:   webhook.AdmitPizzaPath,
+		Validating: webhook.ValidatePizzaPath,
+	},
+	"Order": {
+		Validating:     webhook.ValidateOrderPath,
+		ValidateStatus: true,
+	},
+	"Promotion": {
+		Validating: webhook.ValidatePromotionPath,
+	},
+	"PizzaRecipe": {
+		Validating: webhook.ValidateRecipePath,
+	},
+}
+
+// kind is a kind with the versions it exists in.
+type kind struct {
+	Name     string
+	Versions []*apiVersion
+}
+
+func (k kind) plural() string {
+	return strings.ToLower(k.Name) + "s"
+}
+
+func (k kind) singular() string {
+	return strings.ToLower(k.Name)
+}
+
+func kinds(group *apiGroup) []kind {
+	var result []kind
+	index := map[string]int{}
+	for _, v := range group.Versions {
+		for _, name := range v.Kinds {
+			i, ok := index[name]
+			if !ok {
+				i = len(result)
+				index[name] = i
+				result = append(result, kind{Name: name})
+			}
+			result[i].Versions = append(result[i].Versions, v)
+		}
+	}
+	return result
+}
+
+// generate returns the manifests by file name.
+func generate(group *apiGroup) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	ks := kinds(group)
+	for _, k := range ks {
+		crd, err := customResourceDefinition(group, k)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", k.Name, err)
+		}
+		u, err := toUnstructured(crd)
+		if err != nil {
+			return nil, err
+		}
+		filename := k.singular() + "-crd.yaml"
+		if crd.Spec.Conversion != nil && crd.Spec.Conversion.Strategy == apiextensionsv1.WebhookConverter {
+			if err := unstructured.SetNestedField(u, caBundlePlaceholder, "spec", "conversion", "webhook", "clientConfig", "caBundle"); err != nil {
+				return nil, err
+			}
+			filename += ".template"
+		}
+		if files[filename], err = render(u); err != nil {
+			return nil, err
+		}
+	}
+
+	mutating, validating := webhookConfigurations(group, ks)
+	for filename, obj := range map[string]runtime.Object{
+		"mutatingadmissionregistration.yaml.template":   mutating,
+		"validatingadmissionregistration.yaml.template": validating,
+	} {
+		u, err := toUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		webhooks, _, _ := unstructured.NestedSlice(u, "webhooks")
+		for _, w := range webhooks {
+			if err := unstructured.SetNestedField(w.(map[string]interface{}), caBundlePlaceholder, "clientConfig", "caBundle"); err != nil {
+				return nil, err
+			}
+		}
+		if err := unstructured.SetNestedSlice(u, webhooks, "webhooks"); err != nil {
+			return nil, err
+		}
+		if files[filename], err = render(u); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func customResourceDefinition(group *apiGroup, k kind) (*apiextensionsv1.CustomResourceDefinition, error) {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: k.plural() + "." + group.Name,
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: group.Name,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:     k.Name,
+				ListKind: k.Name + "List",
+				Plural:   k.plural(),
+				Singular: k.singular(),
+			},
+			Scope: apiextensionsv1.NamespaceScoped,
+		},
+	}
+
+	storageVersions := 0
+	for _, v := range k.Versions {
+		t := v.Types[k.Name]
+		schema, err := v.schema(t.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", v.Name, err)
+		}
+		schema.Description = t.Description
+		if t.Markers.has("genclient:nonNamespaced") {
+			crd.Spec.Scope = apiextensionsv1.ClusterScoped
+		}
+
+		crdVersion := apiextensionsv1.CustomResourceDefinitionVersion{
+			Name:    v.Name,
+			Served:  true,
+			Storage: len(k.Versions) == 1 || t.Markers.has("kubebuilder:storageversion"),
+			Schema: &apiextensionsv1.CustomResourceValidation{
+				OpenAPIV3Schema: schema,
+			},
+		}
+		if crdVersion.Storage {
+			storageVersions++
+		}
+		if t.Markers.has("kubebuilder:subresource:status") {
+			crdVersion.Subresources = &apiextensionsv1.CustomResourceSubresources{
+				Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+			}
+		}
+		crd.Spec.Versions = append(crd.Spec.Versions, crdVersion)
+	}
+	if storageVersions != 1 {
+		return nil, fmt.Errorf("expected exactly one version marked with +kubebuilder:storageversion, found %d", storageVersions)
+	}
+
+	if len(k.Versions) > 1 {
+		path := webhooks[k.Name].Conversion
+		if len(path) == 0 {
+			return nil, fmt.Errorf("multiple versions but no conversion webhook")
+		}
+		crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
+			Strategy: apiextensionsv1.WebhookConverter,
+			Webhook: &apiextensionsv1.WebhookConversion{
+				ClientConfig: &apiextensionsv1.WebhookClientConfig{
+					Service: &apiextensionsv1.ServiceReference{
+						Namespace: serviceNamespace,
+						Name:      serviceName,
+						Path:      &path,
+					},
+				},
+				ConversionReviewVersions: []string{"v1", "v1beta1"},
+			},
+		}
+	}
+	return crd, nil
+}
+
+func webhookConfigurations(group *apiGroup, ks []kind) (*admissionregistrationv1.MutatingWebhookConfiguration, *admissionregistrationv1.ValidatingWebhookConfiguration) {
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: admissionregistrationv1.SchemeGroupVersion.String(),
+			Kind:       "MutatingWebhookConfiguration",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: group.Name},
+	}
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: admissionregistrationv1.SchemeGroupVersion.String(),
+			Kind:       "ValidatingWebhookConfiguration",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: group.Name},
+	}
+
+	failurePolicy := admissionregistrationv1.Fail
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	for _, k := range ks {
+		paths := webhooks[k.Name]
+		var versions []string
+		for _, v := range k.Versions {
+			versions = append(versions, v.Name)
+		}
+		rule := func(resources ...string) []admissionregistrationv1.RuleWithOperations {
+			return []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{group.Name},
+					APIVersions: versions,
+					Resources:   resources,
+				},
+			}}
+		}
+
+		if len(paths.Mutating) > 0 {
+			path := paths.Mutating
+			mutating.Webhooks = append(mutating.Webhooks, admissionregistrationv1.MutatingWebhook{
+				Name:                    k.plural() + "." + group.Name,
+				ClientConfig:            clientConfig(path),
+				Rules:                   rule(k.plural()),
+				FailurePolicy:           &failurePolicy,
+				SideEffects:             &sideEffects,
+				AdmissionReviewVersions: []string{"v1", "v1beta1"},
+			})
+		}
+		if len(paths.Validating) > 0 {
+			path := paths.Validating
+			resources := []string{k.plural()}
+			if paths.ValidateStatus {
+				resources = append(resources, k.plural()+"/status")
+			}
+			validating.Webhooks = append(validating.Webhooks, admissionregistrationv1.ValidatingWebhook{
+				Name:                    k.plural() + "." + group.Name,
+				ClientConfig:            clientConfig(path),
+				Rules:                   rule(resources...),
+				FailurePolicy:           &failurePolicy,
+				SideEffects:             &sideEffects,
+				AdmissionReviewVersions: []string{"v1", "v1beta1"},
+			})
+		}
+	}
+	return mutating, validating
+}
+
+func clientConfig(path string) admissionregistrationv1.WebhookClientConfig {
+	return admissionregistrationv1.WebhookClientConfig{
+		Service: &admissionregistrationv1.ServiceReference{
+			Namespace: serviceNamespace,
+			Name:      serviceName,
+			Path:      &path,
+		},
+	}
+}
+
+// toUnstructured converts obj without the fields the API server sets.
+func toUnstructured(obj runtime.Object) (map[string]interface{}, error) {
+	bs, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var u map[string]interface{}
+	if err := json.Unmarshal(bs, &u); err != nil {
+		return nil, err
+	}
+	delete(u, "status")
+	unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+	return u, nil
+}
+
+func render(u map[string]interface{}) ([]byte, error) {
+	bs, err := yaml.Marshal(u)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(header), bs...), nil
+}
+
+func sortedNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
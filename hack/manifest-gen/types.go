@@ -0,0 +1,421 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// markers are the "+key=value" comment lines of a type or field.
+type markers map[string]string
+
+func (m markers) has(key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+// apiGroup is the parsed Go source of an API group.
+type apiGroup struct {
+	Name     string
+	Versions []*apiVersion
+}
+
+// apiVersion is the parsed Go source of one version of an API group.
+type apiVersion struct {
+	Name  string
+	Types map[string]*apiType
+	// Kinds are the top-level types marked with +genclient, in source order.
+	Kinds []string
+}
+
+type apiType struct {
+	Name        string
+	Description string
+	Markers     markers
+	Expr        ast.Expr
+}
+
+// parseGroup parses the types of all versions in dir, e.g. pkg/apis/restaurant.
+func parseGroup(dir string) (*apiGroup, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	group := &apiGroup{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if !kubeVersion.MatchString(e.Name()) {
+			continue
+		}
+		v, groupName, err := parseVersion(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if len(group.Name) > 0 && group.Name != groupName {
+			return nil, fmt.Errorf("%s: group %q differs from %q", e.Name(), groupName, group.Name)
+		}
+		group.Name = groupName
+		group.Versions = append(group.Versions, v)
+	}
+	if len(group.Versions) == 0 {
+		return nil, fmt.Errorf("no API versions found in %s", dir)
+	}
+	if len(group.Name) == 0 {
+		return nil, fmt.Errorf("no +groupName marker found in %s", dir)
+	}
+	sort.Slice(group.Versions, func(i, j int) bool {
+		return version.CompareKubeAwareVersionStrings(group.Versions[i].Name, group.Versions[j].Name) < 0
+	})
+	return group, nil
+}
+
+// kubeVersion matches Kubernetes-style version names like v1 or v1beta2.
+var kubeVersion = regexp.MustCompile(`^v[1-9][0-9]*((alpha|beta)[1-9][0-9]*)?$`)
+
+func parseVersion(dir string) (*apiVersion, string, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasPrefix(info.Name(), "zz_generated") && !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(pkgs) != 1 {
+		return nil, "", fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	v := &apiVersion{Name: filepath.Base(dir), Types: map[string]*apiType{}}
+	var groupName string
+	for _, pkg := range pkgs {
+		filenames := make([]string, 0, len(pkg.Files))
+		for name := range pkg.Files {
+			filenames = append(filenames, name)
+		}
+		sort.Strings(filenames)
+
+		for _, filename := range filenames {
+			file := pkg.Files[filename]
+			for _, c := range file.Comments {
+				if m := parseMarkers(c); m.has("groupName") {
+					groupName = m["groupName"]
+				}
+			}
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					doc := ts.Doc
+					if doc == nil && len(gen.Specs) == 1 {
+						doc = gen.Doc
+					}
+					t := &apiType{
+						Name:        ts.Name.Name,
+						Description: description(doc),
+						Markers:     parseMarkers(doc),
+						Expr:        ts.Type,
+					}
+					// markers like +genclient are usually separated from the
+					// doc comment by an empty line.
+					pos := ts.Pos()
+					if doc != nil {
+						pos = doc.Pos()
+					}
+					line := fset.Position(pos).Line
+					for _, c := range file.Comments {
+						if fset.Position(c.End()).Line == line-2 {
+							for k, val := range parseMarkers(c) {
+								t.Markers[k] = val
+							}
+						}
+					}
+					v.Types[t.Name] = t
+					if t.Markers.has("genclient") {
+						v.Kinds = append(v.Kinds, t.Name)
+					}
+				}
+			}
+		}
+	}
+	return v, groupName, nil
+}
+
+func parseMarkers(c *ast.CommentGroup) markers {
+	m := markers{}
+	if c == nil {
+		return m
+	}
+	for _, line := range strings.Split(c.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "+") {
+			continue
+		}
+		key, value, _ := strings.Cut(line[1:], "=")
+		m[key] = value
+	}
+	return m
+}
+
+// description returns the comment without marker lines as one paragraph.
+func description(c *ast.CommentGroup) string {
+	if c == nil {
+		return ""
+	}
+	var lines []string
+	for _, line := range strings.Split(c.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "+") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, " ")
+}
+
+// schema returns the OpenAPI schema of a Go type expression of v.
+func (v *apiVersion) schema(expr ast.Expr) (*apiextensionsv1.JSONSchemaProps, error) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return &apiextensionsv1.JSONSchemaProps{Type: "string"}, nil
+		case "bool":
+			return &apiextensionsv1.JSONSchemaProps{Type: "boolean"}, nil
+		case "int", "int64", "uint32", "uint64":
+			return &apiextensionsv1.JSONSchemaProps{Type: "integer", Format: "int64"}, nil
+		case "int32", "int16", "int8", "uint16", "uint8":
+			return &apiextensionsv1.JSONSchemaProps{Type: "integer", Format: "int32"}, nil
+		case "float64", "float32":
+			return &apiextensionsv1.JSONSchemaProps{Type: "number"}, nil
+		}
+		named, ok := v.Types[t.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %s", t.Name)
+		}
+		s, err := v.schema(named.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", t.Name, err)
+		}
+		if err := applyMarkers(s, named.Markers); err != nil {
+			return nil, fmt.Errorf("%s: %v", t.Name, err)
+		}
+		return s, nil
+	case *ast.StarExpr:
+		return v.schema(t.X)
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return &apiextensionsv1.JSONSchemaProps{Type: "string", Format: "byte"}, nil
+		}
+		items, err := v.schema(t.Elt)
+		if err != nil {
+			return nil, err
+		}
+		return &apiextensionsv1.JSONSchemaProps{
+			Type:  "array",
+			Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: items},
+		}, nil
+	case *ast.MapType:
+		if ident, ok := t.Key.(*ast.Ident); !ok || ident.Name != "string" {
+			return nil, fmt.Errorf("unsupported map key type %v", t.Key)
+		}
+		values, err := v.schema(t.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &apiextensionsv1.JSONSchemaProps{
+			Type:                 "object",
+			AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Allows: true, Schema: values},
+		}, nil
+	case *ast.SelectorExpr:
+		if s, ok := externalSchemas[selectorName(t)]; ok {
+			return s.DeepCopy(), nil
+		}
+		return nil, fmt.Errorf("unsupported type %s", selectorName(t))
+	case *ast.StructType:
+		return v.structSchema(t)
+	default:
+		return nil, fmt.Errorf("unsupported type expression %T", expr)
+	}
+}
+
+// externalSchemas are the schemas of types from other packages.
+var externalSchemas = map[string]*apiextensionsv1.JSONSchemaProps{
+	"metav1.Time":     {Type: "string", Format: "date-time"},
+	"metav1.Duration": {Type: "string"},
+	"metav1.LabelSelector": {
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"matchLabels": {
+				Type:                 "object",
+				AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Allows: true, Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}},
+			},
+			"matchExpressions": {
+				Type: "array",
+				Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+					Type:     "object",
+					Required: []string{"key", "operator"},
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{
+						"key":      {Type: "string"},
+						"operator": {Type: "string"},
+						"values": {
+							Type:  "array",
+							Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}},
+						},
+					},
+				}},
+			},
+		},
+	},
+	"metav1.Condition": {
+		Type:     "object",
+		Required: []string{"type", "status", "lastTransitionTime", "reason", "message"},
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"type":               {Type: "string", MaxLength: ptrInt64(316)},
+			"status":             {Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"True"`)}, {Raw: []byte(`"False"`)}, {Raw: []byte(`"Unknown"`)}}},
+			"observedGeneration": {Type: "integer", Format: "int64", Minimum: ptrFloat64(0)},
+			"lastTransitionTime": {Type: "string", Format: "date-time"},
+			"reason":             {Type: "string", MaxLength: ptrInt64(1024), MinLength: ptrInt64(1)},
+			"message":            {Type: "string", MaxLength: ptrInt64(32768)},
+		},
+	},
+}
+
+// embeddedMeta are the embedded types whose fields the API server validates.
+var embeddedMeta = map[string]bool{
+	"metav1.TypeMeta":   true,
+	"metav1.ObjectMeta": true,
+	"metav1.ListMeta":   true,
+}
+
+func selectorName(s *ast.SelectorExpr) string {
+	if x, ok := s.X.(*ast.Ident); ok {
+		return x.Name + "." + s.Sel.Name
+	}
+	return s.Sel.Name
+}
+
+func (v *apiVersion) structSchema(st *ast.StructType) (*apiextensionsv1.JSONSchemaProps, error) {
+	s := &apiextensionsv1.JSONSchemaProps{
+		Type:       "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{},
+	}
+	for _, f := range st.Fields.List {
+		if sel, ok := f.Type.(*ast.SelectorExpr); ok && embeddedMeta[selectorName(sel)] {
+			continue
+		}
+		if len(f.Names) == 0 {
+			return nil, fmt.Errorf("unsupported embedded field %v", f.Type)
+		}
+		if f.Tag == nil {
+			return nil, fmt.Errorf("field %s has no json tag", f.Names[0].Name)
+		}
+		tag, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			return nil, err
+		}
+		name, opts, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if len(name) == 0 {
+			return nil, fmt.Errorf("field %s has no json name", f.Names[0].Name)
+		}
+
+		prop, err := v.schema(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", f.Names[0].Name, err)
+		}
+		m := parseMarkers(f.Doc)
+		if err := applyMarkers(prop, m); err != nil {
+			return nil, fmt.Errorf("field %s: %v", f.Names[0].Name, err)
+		}
+		prop.Description = description(f.Doc)
+		s.Properties[name] = *prop
+
+		omitEmpty := false
+		for _, opt := range strings.Split(opts, ",") {
+			omitEmpty = omitEmpty || opt == "omitempty"
+		}
+		if m.has("required") || (!omitEmpty && !m.has("optional")) {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s, nil
+}
+
+const validationPrefix = "kubebuilder:validation:"
+
+// applyMarkers adds the +kubebuilder:validation markers to s.
+func applyMarkers(s *apiextensionsv1.JSONSchemaProps, m markers) error {
+	for key, value := range m {
+		switch key {
+		case "listType":
+			listType := value
+			s.XListType = &listType
+			continue
+		case "listMapKey":
+			s.XListMapKeys = append(s.XListMapKeys, value)
+			continue
+		}
+		if !strings.HasPrefix(key, validationPrefix) {
+			continue
+		}
+		var err error
+		switch strings.TrimPrefix(key, validationPrefix) {
+		case "Minimum":
+			s.Minimum, err = parseFloat(value)
+		case "Maximum":
+			s.Maximum, err = parseFloat(value)
+		case "MinLength":
+			s.MinLength, err = parseInt(value)
+		case "MaxLength":
+			s.MaxLength, err = parseInt(value)
+		case "MinItems":
+			s.MinItems, err = parseInt(value)
+		case "MaxItems":
+			s.MaxItems, err = parseInt(value)
+		case "Pattern":
+			s.Pattern = value
+		case "Enum":
+			s.Enum = nil
+			for _, e := range strings.Split(value, ";") {
+				s.Enum = append(s.Enum, apiextensionsv1.JSON{Raw: []byte(strconv.Quote(e))})
+			}
+		default:
+			return fmt.Errorf("unknown marker +%s", key)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid marker +%s=%s: %v", key, value, err)
+		}
+	}
+	return nil
+}
+
+func ptrInt64(i int64) *int64 { return &i }
+
+func ptrFloat64(f float64) *float64 { return &f }
+
+func parseFloat(s string) (*float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	return &f, err
+}
+
+func parseInt(s string) (*int64, error) {
+	i, err := strconv.ParseInt(s, 10, 64)
+	return &i, err
+}
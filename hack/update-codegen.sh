@@ -25,9 +25,11 @@ CODEGEN_PKG=${CODEGEN_PKG:-$(cd ${SCRIPT_ROOT}; ls -d -1 ./vendor/k8s.io/code-ge
 # --output-base    because this script should also be able to run inside the vendor dir of
 #                  k8s.io/kubernetes. The output-base is needed for the generators to output into the vendor dir
 #                  instead of the $GOPATH directly. For normal projects this can be dropped.
-bash ${CODEGEN_PKG}/generate-groups.sh all \
+# applyconfiguration generates the apply configurations used by the Apply and
+# ApplyStatus methods of the clientset for server-side apply.
+bash ${CODEGEN_PKG}/generate-groups.sh deepcopy,applyconfiguration,client,lister,informer \
   github.com/zeroisme/pizza-crd/pkg/generated github.com/zeroisme/pizza-crd/pkg/apis \
-  "restaurant:v1alpha1,v1beta1" \
+  "restaurant:v1alpha1,v1beta1,v1beta2" \
   --output-base "$(dirname ${BASH_SOURCE})/../../../.." \
   --go-header-file ${SCRIPT_ROOT}/hack/boilerplate.go.txt
 
@@ -0,0 +1,29 @@
+// Package deployment embeds the manifests of the webhook and controller
+// deployments, with CERT and KEY placeholders for the serving certificate.
+package deployment
+
+import "embed"
+
+// Files are the manifests in the order they have to be applied.
+var Files = []string{
+	"ns.yaml",
+	"topping-crd.yaml",
+	"pizza-crd.yaml.template",
+	"order-crd.yaml",
+	"pizzarecipe-crd.yaml",
+	"promotion-crd.yaml",
+	"rbac.yaml",
+	"rbac-bind.yaml",
+	"sa.yaml",
+	"serving-cert-secret.yaml.template",
+	"service.yaml",
+	"deployment.yaml",
+	"controller-deployment.yaml",
+	"mutatingadmissionregistration.yaml.template",
+	"validatingadmissionregistration.yaml.template",
+}
+
+//go:embed ns.yaml topping-crd.yaml pizza-crd.yaml.template order-crd.yaml pizzarecipe-crd.yaml promotion-crd.yaml rbac.yaml rbac-bind.yaml sa.yaml
+//go:embed serving-cert-secret.yaml.template service.yaml deployment.yaml controller-deployment.yaml
+//go:embed mutatingadmissionregistration.yaml.template validatingadmissionregistration.yaml.template
+var FS embed.FS
@@ -0,0 +1,90 @@
+package v1alpha1
+
+import "time"
+
+// Available returns the units of the stock not reserved, and false if the
+// stock is unlimited.
+func (t *Topping) Available() (int64, bool) {
+	if t.Spec.Stock == nil {
+		return 0, false
+	}
+	return *t.Spec.Stock - t.Status.Reserved, true
+}
+
+// EffectivePhase returns the phase of the topping at now: Retired once the
+// sunset time passed, Active if no phase is set.
+func (t *Topping) EffectivePhase(now time.Time) ToppingPhase {
+	if t.Spec.SunsetTime != nil && !now.Before(t.Spec.SunsetTime.Time) {
+		return ToppingRetired
+	}
+	if len(t.Spec.Phase) == 0 {
+		return ToppingActive
+	}
+	return t.Spec.Phase
+}
+
+// ContainsAllergen returns whether the topping is labelled with allergen.
+func (t *Topping) ContainsAllergen(allergen Allergen) bool {
+	for _, a := range t.Spec.Allergens {
+		if a == allergen {
+			return true
+		}
+	}
+	return false
+}
+
+// Supports returns whether the topping may be on a pizza with the claim.
+// Unknown claims are not supported.
+func (t *Topping) Supports(claim DietaryClaim) bool {
+	switch claim {
+	case DietaryClaimVegetarian:
+		return t.Spec.Vegetarian || t.Spec.Vegan
+	case DietaryClaimVegan:
+		return t.Spec.Vegan
+	case DietaryClaimGlutenFree:
+		return !t.ContainsAllergen(AllergenGluten)
+	}
+	return false
+}
+
+// OffersSize returns whether the topping may be put onto pizzas of size.
+func (t *Topping) OffersSize(size PizzaSize) bool {
+	if len(t.Spec.Sizes) == 0 {
+		return true
+	}
+	return t.sizeCost(size) != nil
+}
+
+// CostFor returns the cost of one instance of the topping on a pizza of
+// size: the cost of the size if set, spec.cost times the multiplier of the
+// size if set, or spec.cost.
+func (t *Topping) CostFor(size PizzaSize) float64 {
+	s := t.sizeCost(size)
+	switch {
+	case s == nil:
+		return t.Spec.Cost
+	case s.Cost != nil:
+		return *s.Cost
+	case s.Multiplier != nil:
+		return t.Spec.Cost * *s.Multiplier
+	}
+	return t.Spec.Cost
+}
+
+func (t *Topping) sizeCost(size PizzaSize) *ToppingSize {
+	for i := range t.Spec.Sizes {
+		if t.Spec.Sizes[i].Size == size {
+			return &t.Spec.Sizes[i]
+		}
+	}
+	return nil
+}
+
+// SizeOf returns the size recorded in the size annotation of a pizza in the
+// versions without spec.size, or the default size.
+func SizeOf(annotations map[string]string) PizzaSize {
+	if size, ok := annotations[SizeAnnotation]; ok && len(size) > 0 {
+		return PizzaSize(size)
+	}
+	return DefaultPizzaSize
+}
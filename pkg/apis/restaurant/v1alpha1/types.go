@@ -20,27 +20,188 @@ import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
 
 // Pizza specifies an offered pizza with toppings.
 type Pizza struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
 
+	// +required
 	Spec   PizzaSpec   `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
 	Status PizzaStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
 }
 
+// SizeAnnotation holds the size of a Pizza in the versions without
+// spec.size, so that the size survives conversions.
+const SizeAnnotation = "restaurant.programming-kubernetes.info/size"
+
+// SectionsAnnotation holds the sections of a Pizza as JSON in the versions
+// without spec.sections. The toppings of the sections are part of
+// spec.toppings there, so that older clients see all toppings.
+const SectionsAnnotation = "restaurant.programming-kubernetes.info/sections"
+
+// PizzaSize is the size of a Pizza.
+// +kubebuilder:validation:Enum=small;medium;large;family
+type PizzaSize string
+
+const (
+	PizzaSizeSmall  PizzaSize = "small"
+	PizzaSizeMedium PizzaSize = "medium"
+	PizzaSizeLarge  PizzaSize = "large"
+	PizzaSizeFamily PizzaSize = "family"
+
+	// DefaultPizzaSize is the size of pizzas without a size.
+	DefaultPizzaSize = PizzaSizeMedium
+)
+
+// PizzaSizes are all pizza sizes, from small to large.
+var PizzaSizes = []PizzaSize{PizzaSizeSmall, PizzaSizeMedium, PizzaSizeLarge, PizzaSizeFamily}
+
 type PizzaSpec struct {
 	// +k8s:conversion-gen=false
 	// toppings is a list of Topping names. They don't have to be unique. Order does not matter.
-	Toppings []string `json:"toppings" protobuf:"bytes,1,rep,name=toppings"`
+	// They may be empty if the pizza refers to a recipe.
+	// +optional
+	Toppings []string `json:"toppings,omitempty" protobuf:"bytes,1,rep,name=toppings"`
+	// dietaryClaims are the diets the pizza is advertised as suitable for.
+	// Every topping has to support each claim.
+	// +optional
+	// +listType=set
+	DietaryClaims []DietaryClaim `json:"dietaryClaims,omitempty" protobuf:"bytes,2,rep,name=dietaryClaims,casttype=DietaryClaim"`
+	// recipeRef refers to a PizzaRecipe in the namespace of the pizza the
+	// toppings are taken from, see the mode of the recipe.
+	// +optional
+	RecipeRef *PizzaRecipeReference `json:"recipeRef,omitempty" protobuf:"bytes,3,opt,name=recipeRef"`
+	// additions are Topping names put onto the pizza in addition to the
+	// toppings of the recipe. They don't have to be unique.
+	// +optional
+	Additions []string `json:"additions,omitempty" protobuf:"bytes,4,rep,name=additions"`
+	// removals are the names of toppings of the recipe left off the pizza.
+	// +optional
+	// +listType=set
+	Removals []string `json:"removals,omitempty" protobuf:"bytes,5,rep,name=removals"`
 }
 
+// PizzaRecipeReference refers to a PizzaRecipe in the namespace of a Pizza.
+type PizzaRecipeReference struct {
+	// name is the name of the PizzaRecipe.
+	Name string `json:"name" protobuf:"bytes,1,name=name"`
+}
+
+// DietaryClaim is a diet a Pizza is suitable for.
+// +kubebuilder:validation:Enum=Vegetarian;Vegan;GlutenFree
+type DietaryClaim string
+
+const (
+	DietaryClaimVegetarian DietaryClaim = "Vegetarian"
+	DietaryClaimVegan      DietaryClaim = "Vegan"
+	DietaryClaimGlutenFree DietaryClaim = "GlutenFree"
+)
+
 type PizzaStatus struct {
-	// cost is the cost of the whole pizza including all toppings.
+	// cost is the cost of the whole pizza including all toppings, after the
+	// discounts of promotions.
 	Cost float64 `json:"cost,omitempty" protobuf:"bytes,1,opt,name=cost"`
+	// conditions describe the state of the pizza, e.g. whether the stock of
+	// its toppings is reserved.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" protobuf:"bytes,2,rep,name=conditions"`
+	// allergens are the allergens of all toppings, in alphabetical order.
+	// +optional
+	// +listType=set
+	Allergens []Allergen `json:"allergens,omitempty" protobuf:"bytes,3,rep,name=allergens,casttype=Allergen"`
+	// nutrition is the nutrition of all toppings together. It is unset if
+	// some topping has no nutrition data, which the NutritionComplete
+	// condition reports.
+	// +optional
+	Nutrition *Nutrition `json:"nutrition,omitempty" protobuf:"bytes,4,opt,name=nutrition"`
+	// recipe tells which generation of its recipe the pizza got its toppings
+	// from. For recipes with the Resolve mode it holds the toppings, which
+	// are on the pizza in addition to spec.toppings.
+	// +optional
+	Recipe *RecipeStatus `json:"recipe,omitempty" protobuf:"bytes,5,opt,name=recipe"`
+	// costBreakdown itemizes how the cost follows from the toppings and the
+	// promotions applying to the pizza.
+	// +optional
+	CostBreakdown *CostBreakdown `json:"costBreakdown,omitempty" protobuf:"bytes,6,opt,name=costBreakdown"`
+}
+
+// RecipeStatus is the state of the recipe of a Pizza.
+type RecipeStatus struct {
+	// toppings are the toppings of the recipe with the additions and
+	// removals of the pizza applied, if the recipe has the Resolve mode. They
+	// don't have to be unique.
+	// +optional
+	Toppings []string `json:"toppings,omitempty" protobuf:"bytes,1,rep,name=toppings"`
+	// recipeGeneration is the generation of the recipe the toppings were
+	// resolved from.
+	RecipeGeneration int64 `json:"recipeGeneration" protobuf:"varint,2,name=recipeGeneration"`
+	// observedGeneration is the generation of the pizza the toppings were
+	// resolved for.
+	ObservedGeneration int64 `json:"observedGeneration" protobuf:"varint,3,name=observedGeneration"`
+}
+
+// CostBreakdown is the cost of a Pizza before and after discounts.
+type CostBreakdown struct {
+	// base is the cost of all toppings.
+	Base float64 `json:"base" protobuf:"bytes,1,name=base"`
+	// discounts are the discounts of the promotions in the order they were
+	// applied.
+	// +optional
+	Discounts []AppliedDiscount `json:"discounts,omitempty" protobuf:"bytes,2,rep,name=discounts"`
+	// final is the cost after all discounts, which is never negative.
+	Final float64 `json:"final" protobuf:"bytes,3,name=final"`
+}
+
+// AppliedDiscount is the discount a Promotion granted on a Pizza.
+type AppliedDiscount struct {
+	// promotion is the name of the Promotion.
+	Promotion string `json:"promotion" protobuf:"bytes,1,name=promotion"`
+	// amount is the amount taken off the cost.
+	Amount float64 `json:"amount" protobuf:"bytes,2,name=amount"`
 }
 
+// Nutrition is the nutrition of food.
+type Nutrition struct {
+	// calories is the energy in kcal.
+	// +kubebuilder:validation:Minimum=0
+	Calories float64 `json:"calories" protobuf:"bytes,1,name=calories"`
+	// protein is the protein in grams.
+	// +kubebuilder:validation:Minimum=0
+	Protein float64 `json:"protein" protobuf:"bytes,2,name=protein"`
+	// fat is the fat in grams.
+	// +kubebuilder:validation:Minimum=0
+	Fat float64 `json:"fat" protobuf:"bytes,3,name=fat"`
+	// salt is the salt in grams.
+	// +kubebuilder:validation:Minimum=0
+	Salt float64 `json:"salt" protobuf:"bytes,4,name=salt"`
+}
+
+// Allergen is one of the allergens food has to be labelled with.
+// +kubebuilder:validation:Enum=Celery;Crustaceans;Eggs;Fish;Gluten;Lupin;Milk;Molluscs;Mustard;Nuts;Peanuts;Sesame;Soybeans;Sulphites
+type Allergen string
+
+const (
+	AllergenCelery      Allergen = "Celery"
+	AllergenCrustaceans Allergen = "Crustaceans"
+	AllergenEggs        Allergen = "Eggs"
+	AllergenFish        Allergen = "Fish"
+	AllergenGluten      Allergen = "Gluten"
+	AllergenLupin       Allergen = "Lupin"
+	AllergenMilk        Allergen = "Milk"
+	AllergenMolluscs    Allergen = "Molluscs"
+	AllergenMustard     Allergen = "Mustard"
+	AllergenNuts        Allergen = "Nuts"
+	AllergenPeanuts     Allergen = "Peanuts"
+	AllergenSesame      Allergen = "Sesame"
+	AllergenSoybeans    Allergen = "Soybeans"
+	AllergenSulphites   Allergen = "Sulphites"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // PizzaList is a list of Pizza objects.
@@ -54,18 +215,111 @@ type PizzaList struct {
 // +genclient
 // +genclient:nonNamespaced
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
 
 // Topping is a topping put onto a pizza.
 type Topping struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
 
-	Spec ToppingSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	// +required
+	Spec   ToppingSpec   `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	Status ToppingStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
 }
 
 type ToppingSpec struct {
 	// cost is the cost of one instance of this topping.
+	// +kubebuilder:validation:Minimum=0
 	Cost float64 `json:"cost" protobuf:"bytes,1,name=cost"`
+	// stock is the number of units available for all pizzas together. Pizzas
+	// reserve units of the stock. The stock is unlimited if unset.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Stock *int64 `json:"stock,omitempty" protobuf:"varint,2,opt,name=stock"`
+	// phase is the lifecycle phase of the topping. Deprecated toppings can
+	// still be put onto new pizzas, retired toppings only stay on the pizzas
+	// which have them already. Defaults to Active.
+	// +optional
+	Phase ToppingPhase `json:"phase,omitempty" protobuf:"bytes,3,opt,name=phase,casttype=ToppingPhase"`
+	// sunsetTime is when the topping is retired, whatever its phase.
+	// +optional
+	SunsetTime *metav1.Time `json:"sunsetTime,omitempty" protobuf:"bytes,4,opt,name=sunsetTime"`
+	// excludes are the names of Toppings which must not be on the same pizza
+	// as this topping.
+	// +optional
+	// +listType=set
+	Excludes []string `json:"excludes,omitempty" protobuf:"bytes,5,rep,name=excludes"`
+	// requires are the names of Toppings which must be on every pizza with
+	// this topping.
+	// +optional
+	// +listType=set
+	Requires []string `json:"requires,omitempty" protobuf:"bytes,6,rep,name=requires"`
+	// maxQuantityPerPizza is how often the topping may be put onto one pizza
+	// at most. It is unlimited if unset.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxQuantityPerPizza *int32 `json:"maxQuantityPerPizza,omitempty" protobuf:"varint,7,opt,name=maxQuantityPerPizza"`
+	// allergens are the allergens the topping contains, e.g. Gluten.
+	// +optional
+	// +listType=set
+	Allergens []Allergen `json:"allergens,omitempty" protobuf:"bytes,8,rep,name=allergens,casttype=Allergen"`
+	// vegetarian is whether the topping is suitable for vegetarians.
+	// +optional
+	Vegetarian bool `json:"vegetarian,omitempty" protobuf:"varint,9,opt,name=vegetarian"`
+	// vegan is whether the topping is suitable for vegans. Vegan toppings are
+	// vegetarian as well.
+	// +optional
+	Vegan bool `json:"vegan,omitempty" protobuf:"varint,10,opt,name=vegan"`
+	// nutrition is the nutrition of one unit of the topping.
+	// +optional
+	Nutrition *Nutrition `json:"nutrition,omitempty" protobuf:"bytes,11,opt,name=nutrition"`
+	// sizes are the pizza sizes the topping is offered for, with its cost on
+	// each size. The topping is offered for all sizes at spec.cost if unset.
+	// +optional
+	// +listType=map
+	// +listMapKey=size
+	Sizes []ToppingSize `json:"sizes,omitempty" protobuf:"bytes,12,rep,name=sizes"`
+}
+
+// ToppingSize is the cost of a Topping on pizzas of one size.
+type ToppingSize struct {
+	// size is the size of the pizza.
+	Size PizzaSize `json:"size" protobuf:"bytes,1,name=size,casttype=PizzaSize"`
+	// cost is the cost of one instance of the topping on pizzas of this size.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Cost *float64 `json:"cost,omitempty" protobuf:"bytes,2,opt,name=cost"`
+	// multiplier is multiplied with spec.cost if cost is unset. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Multiplier *float64 `json:"multiplier,omitempty" protobuf:"bytes,3,opt,name=multiplier"`
+}
+
+// ToppingPhase is a phase in the lifecycle of a Topping.
+// +kubebuilder:validation:Enum=Active;Deprecated;Retired
+type ToppingPhase string
+
+const (
+	ToppingActive     ToppingPhase = "Active"
+	ToppingDeprecated ToppingPhase = "Deprecated"
+	ToppingRetired    ToppingPhase = "Retired"
+)
+
+type ToppingStatus struct {
+	// reserved is the number of units of the stock reserved by pizzas.
+	// +optional
+	Reserved int64 `json:"reserved,omitempty" protobuf:"varint,1,opt,name=reserved"`
+	// reservations are the units reserved per pizza, by namespace/name of the pizza.
+	// +optional
+	Reservations map[string]int64 `json:"reservations,omitempty" protobuf:"bytes,2,rep,name=reservations"`
+	// phase is the effective lifecycle phase, Retired once the sunset time
+	// passed.
+	// +optional
+	Phase ToppingPhase `json:"phase,omitempty" protobuf:"bytes,3,opt,name=phase,casttype=ToppingPhase"`
+	// affectedPizzas are the namespace/name of the pizzas which still have
+	// the topping while it is deprecated or retired, in alphabetical order.
+	// +optional
+	AffectedPizzas []string `json:"affectedPizzas,omitempty" protobuf:"bytes,4,rep,name=affectedPizzas"`
 }
 
 // +genclient:nonNamespaced
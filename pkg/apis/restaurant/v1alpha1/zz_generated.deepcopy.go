@@ -22,16 +22,70 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedDiscount) DeepCopyInto(out *AppliedDiscount) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedDiscount.
+func (in *AppliedDiscount) DeepCopy() *AppliedDiscount {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedDiscount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostBreakdown) DeepCopyInto(out *CostBreakdown) {
+	*out = *in
+	if in.Discounts != nil {
+		in, out := &in.Discounts, &out.Discounts
+		*out = make([]AppliedDiscount, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostBreakdown.
+func (in *CostBreakdown) DeepCopy() *CostBreakdown {
+	if in == nil {
+		return nil
+	}
+	out := new(CostBreakdown)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Nutrition) DeepCopyInto(out *Nutrition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Nutrition.
+func (in *Nutrition) DeepCopy() *Nutrition {
+	if in == nil {
+		return nil
+	}
+	out := new(Nutrition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Pizza) DeepCopyInto(out *Pizza) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -86,6 +140,22 @@ func (in *PizzaList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaRecipeReference) DeepCopyInto(out *PizzaRecipeReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaRecipeReference.
+func (in *PizzaRecipeReference) DeepCopy() *PizzaRecipeReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaRecipeReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PizzaSpec) DeepCopyInto(out *PizzaSpec) {
 	*out = *in
@@ -94,6 +164,26 @@ func (in *PizzaSpec) DeepCopyInto(out *PizzaSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DietaryClaims != nil {
+		in, out := &in.DietaryClaims, &out.DietaryClaims
+		*out = make([]DietaryClaim, len(*in))
+		copy(*out, *in)
+	}
+	if in.RecipeRef != nil {
+		in, out := &in.RecipeRef, &out.RecipeRef
+		*out = new(PizzaRecipeReference)
+		**out = **in
+	}
+	if in.Additions != nil {
+		in, out := &in.Additions, &out.Additions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Removals != nil {
+		in, out := &in.Removals, &out.Removals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -110,6 +200,33 @@ func (in *PizzaSpec) DeepCopy() *PizzaSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PizzaStatus) DeepCopyInto(out *PizzaStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Allergens != nil {
+		in, out := &in.Allergens, &out.Allergens
+		*out = make([]Allergen, len(*in))
+		copy(*out, *in)
+	}
+	if in.Nutrition != nil {
+		in, out := &in.Nutrition, &out.Nutrition
+		*out = new(Nutrition)
+		**out = **in
+	}
+	if in.Recipe != nil {
+		in, out := &in.Recipe, &out.Recipe
+		*out = new(RecipeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CostBreakdown != nil {
+		in, out := &in.CostBreakdown, &out.CostBreakdown
+		*out = new(CostBreakdown)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -123,12 +240,34 @@ func (in *PizzaStatus) DeepCopy() *PizzaStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecipeStatus) DeepCopyInto(out *RecipeStatus) {
+	*out = *in
+	if in.Toppings != nil {
+		in, out := &in.Toppings, &out.Toppings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecipeStatus.
+func (in *RecipeStatus) DeepCopy() *RecipeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RecipeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Topping) DeepCopyInto(out *Topping) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -183,9 +322,76 @@ func (in *ToppingList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToppingSize) DeepCopyInto(out *ToppingSize) {
+	*out = *in
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Multiplier != nil {
+		in, out := &in.Multiplier, &out.Multiplier
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToppingSize.
+func (in *ToppingSize) DeepCopy() *ToppingSize {
+	if in == nil {
+		return nil
+	}
+	out := new(ToppingSize)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ToppingSpec) DeepCopyInto(out *ToppingSpec) {
 	*out = *in
+	if in.Stock != nil {
+		in, out := &in.Stock, &out.Stock
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SunsetTime != nil {
+		in, out := &in.SunsetTime, &out.SunsetTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Excludes != nil {
+		in, out := &in.Excludes, &out.Excludes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Requires != nil {
+		in, out := &in.Requires, &out.Requires
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxQuantityPerPizza != nil {
+		in, out := &in.MaxQuantityPerPizza, &out.MaxQuantityPerPizza
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Allergens != nil {
+		in, out := &in.Allergens, &out.Allergens
+		*out = make([]Allergen, len(*in))
+		copy(*out, *in)
+	}
+	if in.Nutrition != nil {
+		in, out := &in.Nutrition, &out.Nutrition
+		*out = new(Nutrition)
+		**out = **in
+	}
+	if in.Sizes != nil {
+		in, out := &in.Sizes, &out.Sizes
+		*out = make([]ToppingSize, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -198,3 +404,31 @@ func (in *ToppingSpec) DeepCopy() *ToppingSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToppingStatus) DeepCopyInto(out *ToppingStatus) {
+	*out = *in
+	if in.Reservations != nil {
+		in, out := &in.Reservations, &out.Reservations
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AffectedPizzas != nil {
+		in, out := &in.AffectedPizzas, &out.AffectedPizzas
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToppingStatus.
+func (in *ToppingStatus) DeepCopy() *ToppingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ToppingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,37 @@
+package v1beta1
+
+// orderTransitions are the phases an Order may move to from each phase.
+var orderTransitions = map[OrderPhase][]OrderPhase{
+	OrderPending:  {OrderAccepted, OrderCancelled},
+	OrderAccepted: {OrderBaking, OrderCancelled},
+	OrderBaking:   {OrderReady, OrderCancelled},
+	OrderReady:    {OrderDelivered, OrderCancelled},
+}
+
+// Normalized returns Pending for the empty phase.
+func (p OrderPhase) Normalized() OrderPhase {
+	if len(p) == 0 {
+		return OrderPending
+	}
+	return p
+}
+
+// Terminal returns whether no phase follows p.
+func (p OrderPhase) Terminal() bool {
+	return len(orderTransitions[p.Normalized()]) == 0
+}
+
+// CanTransitionTo returns whether an Order may move from p to next directly.
+// Staying in the same phase is allowed.
+func (p OrderPhase) CanTransitionTo(next OrderPhase) bool {
+	p, next = p.Normalized(), next.Normalized()
+	if p == next {
+		return true
+	}
+	for _, allowed := range orderTransitions[p] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,12 @@
+package v1beta1
+
+// AllToppings returns the toppings in spec.toppings followed by the toppings
+// resolved from the recipe of the pizza.
+func (p *Pizza) AllToppings() []PizzaTopping {
+	if p.Status.Recipe == nil {
+		return p.Spec.Toppings
+	}
+	toppings := make([]PizzaTopping, 0, len(p.Spec.Toppings)+len(p.Status.Recipe.Toppings))
+	toppings = append(toppings, p.Spec.Toppings...)
+	return append(toppings, p.Status.Recipe.Toppings...)
+}
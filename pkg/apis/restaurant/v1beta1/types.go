@@ -20,19 +20,56 @@ import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
 
 // Pizza specifies an offered pizza with toppings.
 type Pizza struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
 
+	// +required
 	Spec   PizzaSpec   `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
 	Status PizzaStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
 }
 
 type PizzaSpec struct {
 	// toppings is a list of Topping names. They don't have to be unique. Order does not matter.
-	Toppings []PizzaTopping `json:"toppings" protobuf:"bytes,1,rep,name=toppings"`
+	// They may be empty if the pizza refers to a recipe.
+	// +optional
+	Toppings []PizzaTopping `json:"toppings,omitempty" protobuf:"bytes,1,rep,name=toppings"`
+	// dietaryClaims are the diets the pizza is advertised as suitable for.
+	// Every topping has to support each claim.
+	// +optional
+	// +listType=set
+	DietaryClaims []DietaryClaim `json:"dietaryClaims,omitempty" protobuf:"bytes,2,rep,name=dietaryClaims,casttype=DietaryClaim"`
+	// recipeRef refers to a PizzaRecipe in the namespace of the pizza the
+	// toppings are taken from, see the mode of the recipe.
+	// +optional
+	RecipeRef *PizzaRecipeReference `json:"recipeRef,omitempty" protobuf:"bytes,3,opt,name=recipeRef"`
+	// additions are toppings put onto the pizza in addition to the toppings
+	// of the recipe.
+	// +optional
+	Additions []PizzaTopping `json:"additions,omitempty" protobuf:"bytes,4,rep,name=additions"`
+	// removals are the names of toppings of the recipe left off the pizza.
+	// +optional
+	// +listType=set
+	Removals []string `json:"removals,omitempty" protobuf:"bytes,5,rep,name=removals"`
+}
+
+// DietaryClaim is a diet a Pizza is suitable for.
+// +kubebuilder:validation:Enum=Vegetarian;Vegan;GlutenFree
+type DietaryClaim string
+
+const (
+	DietaryClaimVegetarian DietaryClaim = "Vegetarian"
+	DietaryClaimVegan      DietaryClaim = "Vegan"
+	DietaryClaimGlutenFree DietaryClaim = "GlutenFree"
+)
+
+// PizzaRecipeReference refers to a PizzaRecipe in the namespace of a Pizza.
+type PizzaRecipeReference struct {
+	// name is the name of the PizzaRecipe.
+	Name string `json:"name" protobuf:"bytes,1,name=name"`
 }
 
 type PizzaTopping struct {
@@ -40,14 +77,111 @@ type PizzaTopping struct {
 	Name string `json:"name" protobuf:"bytes,1,name=name"`
 	// quantity is the number of how often the topping is put onto the pizza.
 	// +optional
+	// +kubebuilder:validation:Minimum=1
 	Quantity int `json:"quantity" protobuf:"bytes,2,opt,name=quantity"`
 }
 
 type PizzaStatus struct {
-	// cost is the cost of the whole pizza including all toppings.
+	// cost is the cost of the whole pizza including all toppings, after the
+	// discounts of promotions.
 	Cost float64 `json:"cost,omitempty" protobuf:"bytes,1,opt,name=cost"`
+	// conditions describe the state of the pizza, e.g. whether the stock of
+	// its toppings is reserved.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" protobuf:"bytes,2,rep,name=conditions"`
+	// allergens are the allergens of all toppings, in alphabetical order.
+	// +optional
+	// +listType=set
+	Allergens []Allergen `json:"allergens,omitempty" protobuf:"bytes,3,rep,name=allergens,casttype=Allergen"`
+	// nutrition is the nutrition of all toppings together. It is unset if
+	// some topping has no nutrition data, which the NutritionComplete
+	// condition reports.
+	// +optional
+	Nutrition *Nutrition `json:"nutrition,omitempty" protobuf:"bytes,4,opt,name=nutrition"`
+	// recipe tells which generation of its recipe the pizza got its toppings
+	// from. For recipes with the Resolve mode it holds the toppings, which
+	// are on the pizza in addition to spec.toppings.
+	// +optional
+	Recipe *RecipeStatus `json:"recipe,omitempty" protobuf:"bytes,5,opt,name=recipe"`
+	// costBreakdown itemizes how the cost follows from the toppings and the
+	// promotions applying to the pizza.
+	// +optional
+	CostBreakdown *CostBreakdown `json:"costBreakdown,omitempty" protobuf:"bytes,6,opt,name=costBreakdown"`
 }
 
+// RecipeStatus is the state of the recipe of a Pizza.
+type RecipeStatus struct {
+	// toppings are the toppings of the recipe with the additions and
+	// removals of the pizza applied, if the recipe has the Resolve mode.
+	// +optional
+	Toppings []PizzaTopping `json:"toppings,omitempty" protobuf:"bytes,1,rep,name=toppings"`
+	// recipeGeneration is the generation of the recipe the toppings were
+	// resolved from.
+	RecipeGeneration int64 `json:"recipeGeneration" protobuf:"varint,2,name=recipeGeneration"`
+	// observedGeneration is the generation of the pizza the toppings were
+	// resolved for.
+	ObservedGeneration int64 `json:"observedGeneration" protobuf:"varint,3,name=observedGeneration"`
+}
+
+// CostBreakdown is the cost of a Pizza before and after discounts.
+type CostBreakdown struct {
+	// base is the cost of all toppings.
+	Base float64 `json:"base" protobuf:"bytes,1,name=base"`
+	// discounts are the discounts of the promotions in the order they were
+	// applied.
+	// +optional
+	Discounts []AppliedDiscount `json:"discounts,omitempty" protobuf:"bytes,2,rep,name=discounts"`
+	// final is the cost after all discounts, which is never negative.
+	Final float64 `json:"final" protobuf:"bytes,3,name=final"`
+}
+
+// AppliedDiscount is the discount a Promotion granted on a Pizza.
+type AppliedDiscount struct {
+	// promotion is the name of the Promotion.
+	Promotion string `json:"promotion" protobuf:"bytes,1,name=promotion"`
+	// amount is the amount taken off the cost.
+	Amount float64 `json:"amount" protobuf:"bytes,2,name=amount"`
+}
+
+// Nutrition is the nutrition of food.
+type Nutrition struct {
+	// calories is the energy in kcal.
+	// +kubebuilder:validation:Minimum=0
+	Calories float64 `json:"calories" protobuf:"bytes,1,name=calories"`
+	// protein is the protein in grams.
+	// +kubebuilder:validation:Minimum=0
+	Protein float64 `json:"protein" protobuf:"bytes,2,name=protein"`
+	// fat is the fat in grams.
+	// +kubebuilder:validation:Minimum=0
+	Fat float64 `json:"fat" protobuf:"bytes,3,name=fat"`
+	// salt is the salt in grams.
+	// +kubebuilder:validation:Minimum=0
+	Salt float64 `json:"salt" protobuf:"bytes,4,name=salt"`
+}
+
+// Allergen is one of the allergens food has to be labelled with.
+// +kubebuilder:validation:Enum=Celery;Crustaceans;Eggs;Fish;Gluten;Lupin;Milk;Molluscs;Mustard;Nuts;Peanuts;Sesame;Soybeans;Sulphites
+type Allergen string
+
+const (
+	AllergenCelery      Allergen = "Celery"
+	AllergenCrustaceans Allergen = "Crustaceans"
+	AllergenEggs        Allergen = "Eggs"
+	AllergenFish        Allergen = "Fish"
+	AllergenGluten      Allergen = "Gluten"
+	AllergenLupin       Allergen = "Lupin"
+	AllergenMilk        Allergen = "Milk"
+	AllergenMolluscs    Allergen = "Molluscs"
+	AllergenMustard     Allergen = "Mustard"
+	AllergenNuts        Allergen = "Nuts"
+	AllergenPeanuts     Allergen = "Peanuts"
+	AllergenSesame      Allergen = "Sesame"
+	AllergenSoybeans    Allergen = "Soybeans"
+	AllergenSulphites   Allergen = "Sulphites"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // PizzaList is a list of Pizza objects.
@@ -57,3 +191,89 @@ type PizzaList struct {
 
 	Items []Pizza `json:"items" protobuf:"bytes,2,rep,name=items"`
 }
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+
+// Order is an order of pizzas. It moves through the phases Pending, Accepted,
+// Baking, Ready and Delivered, or is Cancelled before it is delivered.
+type Order struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// +required
+	Spec   OrderSpec   `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	Status OrderStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+type OrderSpec struct {
+	// pizzas are the ordered Pizzas in the namespace of the order. They cannot
+	// be changed once the order is accepted.
+	// +kubebuilder:validation:MinItems=1
+	Pizzas []OrderItem `json:"pizzas" protobuf:"bytes,1,rep,name=pizzas"`
+	// phase is the phase the order is requested to move to. It may only move
+	// one phase forward at a time, or to Cancelled before the order is
+	// delivered. Defaults to Pending.
+	// +optional
+	Phase OrderPhase `json:"phase,omitempty" protobuf:"bytes,2,opt,name=phase,casttype=OrderPhase"`
+}
+
+type OrderItem struct {
+	// name is the name of a Pizza object.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name" protobuf:"bytes,1,name=name"`
+	// quantity is the number of pizzas ordered.
+	// +kubebuilder:validation:Minimum=1
+	Quantity int `json:"quantity" protobuf:"bytes,2,opt,name=quantity"`
+}
+
+// OrderPhase is a phase in the lifecycle of an Order.
+// +kubebuilder:validation:Enum=Pending;Accepted;Baking;Ready;Delivered;Cancelled
+type OrderPhase string
+
+const (
+	OrderPending   OrderPhase = "Pending"
+	OrderAccepted  OrderPhase = "Accepted"
+	OrderBaking    OrderPhase = "Baking"
+	OrderReady     OrderPhase = "Ready"
+	OrderDelivered OrderPhase = "Delivered"
+	OrderCancelled OrderPhase = "Cancelled"
+)
+
+type OrderStatus struct {
+	// phase is the phase the order is in.
+	// +optional
+	Phase OrderPhase `json:"phase,omitempty" protobuf:"bytes,1,opt,name=phase,casttype=OrderPhase"`
+	// phaseTimes are the times the order entered each phase, in order.
+	// +optional
+	PhaseTimes []OrderPhaseTime `json:"phaseTimes,omitempty" protobuf:"bytes,2,rep,name=phaseTimes"`
+	// total is the cost of all ordered pizzas. It is fixed when the order is
+	// accepted.
+	// +optional
+	Total float64 `json:"total,omitempty" protobuf:"bytes,3,opt,name=total"`
+	// message explains why the order does not move to the requested phase or
+	// has no total.
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,4,opt,name=message"`
+	// observedGeneration is the generation of the spec the status reflects.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty" protobuf:"varint,5,opt,name=observedGeneration"`
+}
+
+type OrderPhaseTime struct {
+	// phase is the phase entered.
+	Phase OrderPhase `json:"phase" protobuf:"bytes,1,name=phase,casttype=OrderPhase"`
+	// time is when the phase was entered.
+	Time metav1.Time `json:"time" protobuf:"bytes,2,name=time"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OrderList is a list of Order objects.
+type OrderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []Order `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
@@ -22,16 +22,208 @@ limitations under the License.
 package v1beta1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedDiscount) DeepCopyInto(out *AppliedDiscount) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedDiscount.
+func (in *AppliedDiscount) DeepCopy() *AppliedDiscount {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedDiscount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostBreakdown) DeepCopyInto(out *CostBreakdown) {
+	*out = *in
+	if in.Discounts != nil {
+		in, out := &in.Discounts, &out.Discounts
+		*out = make([]AppliedDiscount, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostBreakdown.
+func (in *CostBreakdown) DeepCopy() *CostBreakdown {
+	if in == nil {
+		return nil
+	}
+	out := new(CostBreakdown)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Nutrition) DeepCopyInto(out *Nutrition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Nutrition.
+func (in *Nutrition) DeepCopy() *Nutrition {
+	if in == nil {
+		return nil
+	}
+	out := new(Nutrition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Order) DeepCopyInto(out *Order) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Order.
+func (in *Order) DeepCopy() *Order {
+	if in == nil {
+		return nil
+	}
+	out := new(Order)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Order) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrderItem) DeepCopyInto(out *OrderItem) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrderItem.
+func (in *OrderItem) DeepCopy() *OrderItem {
+	if in == nil {
+		return nil
+	}
+	out := new(OrderItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrderList) DeepCopyInto(out *OrderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Order, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrderList.
+func (in *OrderList) DeepCopy() *OrderList {
+	if in == nil {
+		return nil
+	}
+	out := new(OrderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OrderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrderPhaseTime) DeepCopyInto(out *OrderPhaseTime) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrderPhaseTime.
+func (in *OrderPhaseTime) DeepCopy() *OrderPhaseTime {
+	if in == nil {
+		return nil
+	}
+	out := new(OrderPhaseTime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrderSpec) DeepCopyInto(out *OrderSpec) {
+	*out = *in
+	if in.Pizzas != nil {
+		in, out := &in.Pizzas, &out.Pizzas
+		*out = make([]OrderItem, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrderSpec.
+func (in *OrderSpec) DeepCopy() *OrderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OrderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrderStatus) DeepCopyInto(out *OrderStatus) {
+	*out = *in
+	if in.PhaseTimes != nil {
+		in, out := &in.PhaseTimes, &out.PhaseTimes
+		*out = make([]OrderPhaseTime, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrderStatus.
+func (in *OrderStatus) DeepCopy() *OrderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OrderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Pizza) DeepCopyInto(out *Pizza) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -86,6 +278,22 @@ func (in *PizzaList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaRecipeReference) DeepCopyInto(out *PizzaRecipeReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaRecipeReference.
+func (in *PizzaRecipeReference) DeepCopy() *PizzaRecipeReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaRecipeReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PizzaSpec) DeepCopyInto(out *PizzaSpec) {
 	*out = *in
@@ -94,6 +302,26 @@ func (in *PizzaSpec) DeepCopyInto(out *PizzaSpec) {
 		*out = make([]PizzaTopping, len(*in))
 		copy(*out, *in)
 	}
+	if in.DietaryClaims != nil {
+		in, out := &in.DietaryClaims, &out.DietaryClaims
+		*out = make([]DietaryClaim, len(*in))
+		copy(*out, *in)
+	}
+	if in.RecipeRef != nil {
+		in, out := &in.RecipeRef, &out.RecipeRef
+		*out = new(PizzaRecipeReference)
+		**out = **in
+	}
+	if in.Additions != nil {
+		in, out := &in.Additions, &out.Additions
+		*out = make([]PizzaTopping, len(*in))
+		copy(*out, *in)
+	}
+	if in.Removals != nil {
+		in, out := &in.Removals, &out.Removals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -110,6 +338,33 @@ func (in *PizzaSpec) DeepCopy() *PizzaSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PizzaStatus) DeepCopyInto(out *PizzaStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Allergens != nil {
+		in, out := &in.Allergens, &out.Allergens
+		*out = make([]Allergen, len(*in))
+		copy(*out, *in)
+	}
+	if in.Nutrition != nil {
+		in, out := &in.Nutrition, &out.Nutrition
+		*out = new(Nutrition)
+		**out = **in
+	}
+	if in.Recipe != nil {
+		in, out := &in.Recipe, &out.Recipe
+		*out = new(RecipeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CostBreakdown != nil {
+		in, out := &in.CostBreakdown, &out.CostBreakdown
+		*out = new(CostBreakdown)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -138,3 +393,24 @@ func (in *PizzaTopping) DeepCopy() *PizzaTopping {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecipeStatus) DeepCopyInto(out *RecipeStatus) {
+	*out = *in
+	if in.Toppings != nil {
+		in, out := &in.Toppings, &out.Toppings
+		*out = make([]PizzaTopping, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecipeStatus.
+func (in *RecipeStatus) DeepCopy() *RecipeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RecipeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,79 @@
+package v1beta2
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ValidAt returns whether the promotion is valid at t. It is valid from
+// validFrom on and until right before validUntil.
+func (p *Promotion) ValidAt(t time.Time) bool {
+	if from := p.Spec.ValidFrom; from != nil && t.Before(from.Time) {
+		return false
+	}
+	if until := p.Spec.ValidUntil; until != nil && !t.Before(until.Time) {
+		return false
+	}
+	return true
+}
+
+// NextTransition returns the first time after t the promotion starts or ends,
+// and false if it neither starts nor ends after t.
+func (p *Promotion) NextTransition(t time.Time) (time.Time, bool) {
+	for _, bound := range []*metav1.Time{p.Spec.ValidFrom, p.Spec.ValidUntil} {
+		if bound != nil && bound.Time.After(t) {
+			return bound.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Matches returns whether the selector selects a pizza in the namespace with
+// the labels and toppings. An invalid pizza selector selects no pizza.
+func (s *PromotionSelector) Matches(namespace string, pizzaLabels map[string]string, hasTopping func(name string) bool) bool {
+	if s.PizzaSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(s.PizzaSelector)
+		if err != nil || !selector.Matches(labels.Set(pizzaLabels)) {
+			return false
+		}
+	}
+	if len(s.Namespaces) > 0 {
+		found := false
+		for _, ns := range s.Namespaces {
+			found = found || ns == namespace
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, name := range s.Toppings {
+		if !hasTopping(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// stage returns the order discounts are applied in: buyNGetOneFree first,
+// then percentOff and then amountOff.
+func (d *PromotionDiscount) stage() int {
+	switch {
+	case d.BuyNGetOneFree != nil:
+		return 0
+	case d.PercentOff != nil:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Less returns whether the discount of p is applied before the one of q,
+// which is the stacking order of promotions.
+func (p *Promotion) Less(q *Promotion) bool {
+	if a, b := p.Spec.Discount.stage(), q.Spec.Discount.stage(); a != b {
+		return a < b
+	}
+	return p.Name < q.Name
+}
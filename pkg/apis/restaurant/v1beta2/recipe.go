@@ -0,0 +1,38 @@
+package v1beta2
+
+// EffectiveMode returns the mode of the recipe, Expand if no mode is set.
+func (r *PizzaRecipe) EffectiveMode() RecipeMode {
+	if len(r.Spec.Mode) == 0 {
+		return RecipeExpand
+	}
+	return r.Spec.Mode
+}
+
+// Expand returns the toppings of the recipe without the removed toppings and
+// with the additions. Additions of a topping the recipe has increase its
+// quantity.
+func (r *PizzaRecipe) Expand(additions []PizzaTopping, removals []string) []PizzaTopping {
+	removed := map[string]bool{}
+	for _, name := range removals {
+		removed[name] = true
+	}
+	toppings := []PizzaTopping{}
+	index := map[string]int{}
+	add := func(t PizzaTopping) {
+		if i, ok := index[t.Name]; ok {
+			toppings[i].Quantity += t.Quantity
+			return
+		}
+		index[t.Name] = len(toppings)
+		toppings = append(toppings, t)
+	}
+	for _, t := range r.Spec.Toppings {
+		if !removed[t.Name] {
+			add(t)
+		}
+	}
+	for _, t := range additions {
+		add(t)
+	}
+	return toppings
+}
@@ -0,0 +1,362 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+
+// Pizza specifies an offered pizza with toppings.
+type Pizza struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// +required
+	Spec   PizzaSpec   `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	Status PizzaStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+type PizzaSpec struct {
+	// toppings is a list of Topping names. They don't have to be unique. Order does not matter.
+	// They are put onto the whole pizza. They may be empty if the pizza has
+	// sections or refers to a recipe.
+	// +optional
+	Toppings []PizzaTopping `json:"toppings,omitempty" protobuf:"bytes,1,rep,name=toppings"`
+	// dietaryClaims are the diets the pizza is advertised as suitable for.
+	// Every topping has to support each claim.
+	// +optional
+	// +listType=set
+	DietaryClaims []DietaryClaim `json:"dietaryClaims,omitempty" protobuf:"bytes,2,rep,name=dietaryClaims,casttype=DietaryClaim"`
+	// size is the size of the pizza. Toppings may cost more on larger pizzas
+	// and may be restricted to some sizes. Defaults to medium.
+	// +optional
+	Size PizzaSize `json:"size,omitempty" protobuf:"bytes,3,opt,name=size,casttype=PizzaSize"`
+	// sections split the pizza, e.g. into halves, each with its own toppings.
+	// The toppings above are put onto the whole pizza in addition. The
+	// coverage of all sections has to sum up to 1.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Sections []PizzaSection `json:"sections,omitempty" protobuf:"bytes,4,rep,name=sections"`
+	// recipeRef refers to a PizzaRecipe in the namespace of the pizza the
+	// toppings are taken from, see the mode of the recipe.
+	// +optional
+	RecipeRef *PizzaRecipeReference `json:"recipeRef,omitempty" protobuf:"bytes,5,opt,name=recipeRef"`
+	// additions are toppings put onto the pizza in addition to the toppings
+	// of the recipe.
+	// +optional
+	Additions []PizzaTopping `json:"additions,omitempty" protobuf:"bytes,6,rep,name=additions"`
+	// removals are the names of toppings of the recipe left off the pizza.
+	// +optional
+	// +listType=set
+	Removals []string `json:"removals,omitempty" protobuf:"bytes,7,rep,name=removals"`
+}
+
+// PizzaSection is a part of a Pizza with its own toppings.
+type PizzaSection struct {
+	// name identifies the section, e.g. left or right.
+	Name string `json:"name" protobuf:"bytes,1,name=name"`
+	// coverage is the fraction of the pizza the section covers. The cost of
+	// the toppings of the section is pro-rated by it.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	Coverage float64 `json:"coverage" protobuf:"bytes,2,name=coverage"`
+	// toppings are put onto the section only.
+	Toppings []PizzaTopping `json:"toppings" protobuf:"bytes,3,rep,name=toppings"`
+}
+
+// PizzaSize is the size of a Pizza.
+// +kubebuilder:validation:Enum=small;medium;large;family
+type PizzaSize string
+
+const (
+	PizzaSizeSmall  PizzaSize = "small"
+	PizzaSizeMedium PizzaSize = "medium"
+	PizzaSizeLarge  PizzaSize = "large"
+	PizzaSizeFamily PizzaSize = "family"
+)
+
+// DietaryClaim is a diet a Pizza is suitable for.
+// +kubebuilder:validation:Enum=Vegetarian;Vegan;GlutenFree
+type DietaryClaim string
+
+const (
+	DietaryClaimVegetarian DietaryClaim = "Vegetarian"
+	DietaryClaimVegan      DietaryClaim = "Vegan"
+	DietaryClaimGlutenFree DietaryClaim = "GlutenFree"
+)
+
+// PizzaRecipeReference refers to a PizzaRecipe in the namespace of a Pizza.
+type PizzaRecipeReference struct {
+	// name is the name of the PizzaRecipe.
+	Name string `json:"name" protobuf:"bytes,1,name=name"`
+}
+
+type PizzaTopping struct {
+	// name is the name of a Topping object .
+	Name string `json:"name" protobuf:"bytes,1,name=name"`
+	// quantity is the number of how often the topping is put onto the pizza.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Quantity int `json:"quantity" protobuf:"bytes,2,opt,name=quantity"`
+}
+
+type PizzaStatus struct {
+	// cost is the cost of the whole pizza including all toppings, after the
+	// discounts of promotions.
+	Cost float64 `json:"cost,omitempty" protobuf:"bytes,1,opt,name=cost"`
+	// conditions describe the state of the pizza, e.g. whether the stock of
+	// its toppings is reserved.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" protobuf:"bytes,2,rep,name=conditions"`
+	// allergens are the allergens of all toppings, in alphabetical order.
+	// +optional
+	// +listType=set
+	Allergens []Allergen `json:"allergens,omitempty" protobuf:"bytes,3,rep,name=allergens,casttype=Allergen"`
+	// nutrition is the nutrition of all toppings together. It is unset if
+	// some topping has no nutrition data, which the NutritionComplete
+	// condition reports.
+	// +optional
+	Nutrition *Nutrition `json:"nutrition,omitempty" protobuf:"bytes,4,opt,name=nutrition"`
+	// recipe tells which generation of its recipe the pizza got its toppings
+	// from. For recipes with the Resolve mode it holds the toppings, which
+	// are on the pizza in addition to spec.toppings.
+	// +optional
+	Recipe *RecipeStatus `json:"recipe,omitempty" protobuf:"bytes,5,opt,name=recipe"`
+	// costBreakdown itemizes how the cost follows from the toppings and the
+	// promotions applying to the pizza.
+	// +optional
+	CostBreakdown *CostBreakdown `json:"costBreakdown,omitempty" protobuf:"bytes,6,opt,name=costBreakdown"`
+}
+
+// RecipeStatus is the state of the recipe of a Pizza.
+type RecipeStatus struct {
+	// toppings are the toppings of the recipe with the additions and
+	// removals of the pizza applied, if the recipe has the Resolve mode.
+	// +optional
+	Toppings []PizzaTopping `json:"toppings,omitempty" protobuf:"bytes,1,rep,name=toppings"`
+	// recipeGeneration is the generation of the recipe the toppings were
+	// resolved from.
+	RecipeGeneration int64 `json:"recipeGeneration" protobuf:"varint,2,name=recipeGeneration"`
+	// observedGeneration is the generation of the pizza the toppings were
+	// resolved for.
+	ObservedGeneration int64 `json:"observedGeneration" protobuf:"varint,3,name=observedGeneration"`
+}
+
+// CostBreakdown is the cost of a Pizza before and after discounts.
+type CostBreakdown struct {
+	// base is the cost of all toppings.
+	Base float64 `json:"base" protobuf:"bytes,1,name=base"`
+	// discounts are the discounts of the promotions in the order they were
+	// applied.
+	// +optional
+	Discounts []AppliedDiscount `json:"discounts,omitempty" protobuf:"bytes,2,rep,name=discounts"`
+	// final is the cost after all discounts, which is never negative.
+	Final float64 `json:"final" protobuf:"bytes,3,name=final"`
+}
+
+// AppliedDiscount is the discount a Promotion granted on a Pizza.
+type AppliedDiscount struct {
+	// promotion is the name of the Promotion.
+	Promotion string `json:"promotion" protobuf:"bytes,1,name=promotion"`
+	// amount is the amount taken off the cost.
+	Amount float64 `json:"amount" protobuf:"bytes,2,name=amount"`
+}
+
+// Nutrition is the nutrition of food.
+type Nutrition struct {
+	// calories is the energy in kcal.
+	// +kubebuilder:validation:Minimum=0
+	Calories float64 `json:"calories" protobuf:"bytes,1,name=calories"`
+	// protein is the protein in grams.
+	// +kubebuilder:validation:Minimum=0
+	Protein float64 `json:"protein" protobuf:"bytes,2,name=protein"`
+	// fat is the fat in grams.
+	// +kubebuilder:validation:Minimum=0
+	Fat float64 `json:"fat" protobuf:"bytes,3,name=fat"`
+	// salt is the salt in grams.
+	// +kubebuilder:validation:Minimum=0
+	Salt float64 `json:"salt" protobuf:"bytes,4,name=salt"`
+}
+
+// Allergen is one of the allergens food has to be labelled with.
+// +kubebuilder:validation:Enum=Celery;Crustaceans;Eggs;Fish;Gluten;Lupin;Milk;Molluscs;Mustard;Nuts;Peanuts;Sesame;Soybeans;Sulphites
+type Allergen string
+
+const (
+	AllergenCelery      Allergen = "Celery"
+	AllergenCrustaceans Allergen = "Crustaceans"
+	AllergenEggs        Allergen = "Eggs"
+	AllergenFish        Allergen = "Fish"
+	AllergenGluten      Allergen = "Gluten"
+	AllergenLupin       Allergen = "Lupin"
+	AllergenMilk        Allergen = "Milk"
+	AllergenMolluscs    Allergen = "Molluscs"
+	AllergenMustard     Allergen = "Mustard"
+	AllergenNuts        Allergen = "Nuts"
+	AllergenPeanuts     Allergen = "Peanuts"
+	AllergenSesame      Allergen = "Sesame"
+	AllergenSoybeans    Allergen = "Soybeans"
+	AllergenSulphites   Allergen = "Sulphites"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PizzaList is a list of Pizza objects.
+type PizzaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []Pizza `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PizzaRecipe is a named set of toppings, e.g. a margherita. Pizzas in the
+// same namespace reference it with spec.recipeRef instead of listing the
+// toppings themselves.
+type PizzaRecipe struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// +required
+	Spec PizzaRecipeSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+type PizzaRecipeSpec struct {
+	// toppings are the toppings of the recipe.
+	// +kubebuilder:validation:MinItems=1
+	Toppings []PizzaTopping `json:"toppings" protobuf:"bytes,1,rep,name=toppings"`
+	// mode is how pizzas get the toppings of the recipe. Defaults to Expand.
+	// +optional
+	Mode RecipeMode `json:"mode,omitempty" protobuf:"bytes,2,opt,name=mode,casttype=RecipeMode"`
+	// propagate updates the pizzas referencing the recipe when its toppings
+	// change. Otherwise pizzas keep the toppings they got until their
+	// recipeRef, additions or removals change.
+	// +optional
+	Propagate bool `json:"propagate,omitempty" protobuf:"varint,3,opt,name=propagate"`
+}
+
+// RecipeMode is how Pizzas get the toppings of their PizzaRecipe.
+// +kubebuilder:validation:Enum=Expand;Resolve
+type RecipeMode string
+
+const (
+	// RecipeExpand lets the mutating webhook write the toppings of the recipe
+	// into spec.toppings of the pizza.
+	RecipeExpand RecipeMode = "Expand"
+	// RecipeResolve lets the recipe controller write the toppings of the
+	// recipe into status.recipe of the pizza, leaving its spec unchanged.
+	RecipeResolve RecipeMode = "Resolve"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PizzaRecipeList is a list of PizzaRecipe objects.
+type PizzaRecipeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []PizzaRecipe `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Promotion is a discount on the cost of the pizzas it selects while it is
+// valid.
+//
+// The cost controller applies all valid promotions selecting a pizza in a
+// fixed order: buyNGetOneFree discounts first, then percentOff and then
+// amountOff, and promotions of the same kind by name. Each discount is taken
+// off the cost left by the ones before, and the cost never gets negative.
+type Promotion struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// +required
+	Spec PromotionSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+type PromotionSpec struct {
+	// validFrom is when the promotion starts. It is valid right away if
+	// unset.
+	// +optional
+	ValidFrom *metav1.Time `json:"validFrom,omitempty" protobuf:"bytes,1,opt,name=validFrom"`
+	// validUntil is when the promotion ends. It is valid forever if unset.
+	// +optional
+	ValidUntil *metav1.Time `json:"validUntil,omitempty" protobuf:"bytes,2,opt,name=validUntil"`
+	// selector selects the pizzas the promotion applies to. An empty
+	// selector selects all pizzas.
+	// +optional
+	Selector PromotionSelector `json:"selector,omitempty" protobuf:"bytes,3,opt,name=selector"`
+	// discount is the discount on the selected pizzas.
+	Discount PromotionDiscount `json:"discount" protobuf:"bytes,4,name=discount"`
+}
+
+// PromotionSelector selects Pizzas. A pizza has to match all of the fields
+// which are set.
+type PromotionSelector struct {
+	// pizzaSelector selects pizzas by their labels.
+	// +optional
+	PizzaSelector *metav1.LabelSelector `json:"pizzaSelector,omitempty" protobuf:"bytes,1,opt,name=pizzaSelector"`
+	// namespaces are the namespaces of the pizzas.
+	// +optional
+	// +listType=set
+	Namespaces []string `json:"namespaces,omitempty" protobuf:"bytes,2,rep,name=namespaces"`
+	// toppings are the names of toppings a pizza has to have all of.
+	// +optional
+	// +listType=set
+	Toppings []string `json:"toppings,omitempty" protobuf:"bytes,3,rep,name=toppings"`
+}
+
+// PromotionDiscount is a discount on the cost of a Pizza. Exactly one of the
+// fields has to be set.
+type PromotionDiscount struct {
+	// percentOff is the percentage taken off the cost.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	PercentOff *float64 `json:"percentOff,omitempty" protobuf:"bytes,1,opt,name=percentOff"`
+	// amountOff is a fixed amount taken off the cost.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	AmountOff *float64 `json:"amountOff,omitempty" protobuf:"bytes,2,opt,name=amountOff"`
+	// buyNGetOneFree makes one topping free for every n toppings paid for,
+	// the cheapest ones first. Only the toppings of the selector count if it
+	// has any.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	BuyNGetOneFree *int32 `json:"buyNGetOneFree,omitempty" protobuf:"varint,3,opt,name=buyNGetOneFree"`
+}
+
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PromotionList is a list of Promotion objects.
+type PromotionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []Promotion `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
@@ -0,0 +1,535 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by deepcopy-gen. DO NOT EDIT.
+
+package v1beta2
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedDiscount) DeepCopyInto(out *AppliedDiscount) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedDiscount.
+func (in *AppliedDiscount) DeepCopy() *AppliedDiscount {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedDiscount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostBreakdown) DeepCopyInto(out *CostBreakdown) {
+	*out = *in
+	if in.Discounts != nil {
+		in, out := &in.Discounts, &out.Discounts
+		*out = make([]AppliedDiscount, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostBreakdown.
+func (in *CostBreakdown) DeepCopy() *CostBreakdown {
+	if in == nil {
+		return nil
+	}
+	out := new(CostBreakdown)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Nutrition) DeepCopyInto(out *Nutrition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Nutrition.
+func (in *Nutrition) DeepCopy() *Nutrition {
+	if in == nil {
+		return nil
+	}
+	out := new(Nutrition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Pizza) DeepCopyInto(out *Pizza) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pizza.
+func (in *Pizza) DeepCopy() *Pizza {
+	if in == nil {
+		return nil
+	}
+	out := new(Pizza)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Pizza) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaList) DeepCopyInto(out *PizzaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Pizza, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaList.
+func (in *PizzaList) DeepCopy() *PizzaList {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PizzaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaRecipe) DeepCopyInto(out *PizzaRecipe) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaRecipe.
+func (in *PizzaRecipe) DeepCopy() *PizzaRecipe {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaRecipe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PizzaRecipe) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaRecipeList) DeepCopyInto(out *PizzaRecipeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PizzaRecipe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaRecipeList.
+func (in *PizzaRecipeList) DeepCopy() *PizzaRecipeList {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaRecipeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PizzaRecipeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaRecipeReference) DeepCopyInto(out *PizzaRecipeReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaRecipeReference.
+func (in *PizzaRecipeReference) DeepCopy() *PizzaRecipeReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaRecipeReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaRecipeSpec) DeepCopyInto(out *PizzaRecipeSpec) {
+	*out = *in
+	if in.Toppings != nil {
+		in, out := &in.Toppings, &out.Toppings
+		*out = make([]PizzaTopping, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaRecipeSpec.
+func (in *PizzaRecipeSpec) DeepCopy() *PizzaRecipeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaRecipeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaSection) DeepCopyInto(out *PizzaSection) {
+	*out = *in
+	if in.Toppings != nil {
+		in, out := &in.Toppings, &out.Toppings
+		*out = make([]PizzaTopping, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaSection.
+func (in *PizzaSection) DeepCopy() *PizzaSection {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaSection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaSpec) DeepCopyInto(out *PizzaSpec) {
+	*out = *in
+	if in.Toppings != nil {
+		in, out := &in.Toppings, &out.Toppings
+		*out = make([]PizzaTopping, len(*in))
+		copy(*out, *in)
+	}
+	if in.DietaryClaims != nil {
+		in, out := &in.DietaryClaims, &out.DietaryClaims
+		*out = make([]DietaryClaim, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sections != nil {
+		in, out := &in.Sections, &out.Sections
+		*out = make([]PizzaSection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RecipeRef != nil {
+		in, out := &in.RecipeRef, &out.RecipeRef
+		*out = new(PizzaRecipeReference)
+		**out = **in
+	}
+	if in.Additions != nil {
+		in, out := &in.Additions, &out.Additions
+		*out = make([]PizzaTopping, len(*in))
+		copy(*out, *in)
+	}
+	if in.Removals != nil {
+		in, out := &in.Removals, &out.Removals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaSpec.
+func (in *PizzaSpec) DeepCopy() *PizzaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaStatus) DeepCopyInto(out *PizzaStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Allergens != nil {
+		in, out := &in.Allergens, &out.Allergens
+		*out = make([]Allergen, len(*in))
+		copy(*out, *in)
+	}
+	if in.Nutrition != nil {
+		in, out := &in.Nutrition, &out.Nutrition
+		*out = new(Nutrition)
+		**out = **in
+	}
+	if in.Recipe != nil {
+		in, out := &in.Recipe, &out.Recipe
+		*out = new(RecipeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CostBreakdown != nil {
+		in, out := &in.CostBreakdown, &out.CostBreakdown
+		*out = new(CostBreakdown)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaStatus.
+func (in *PizzaStatus) DeepCopy() *PizzaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaTopping) DeepCopyInto(out *PizzaTopping) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaTopping.
+func (in *PizzaTopping) DeepCopy() *PizzaTopping {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaTopping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Promotion) DeepCopyInto(out *Promotion) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Promotion.
+func (in *Promotion) DeepCopy() *Promotion {
+	if in == nil {
+		return nil
+	}
+	out := new(Promotion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Promotion) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionDiscount) DeepCopyInto(out *PromotionDiscount) {
+	*out = *in
+	if in.PercentOff != nil {
+		in, out := &in.PercentOff, &out.PercentOff
+		*out = new(float64)
+		**out = **in
+	}
+	if in.AmountOff != nil {
+		in, out := &in.AmountOff, &out.AmountOff
+		*out = new(float64)
+		**out = **in
+	}
+	if in.BuyNGetOneFree != nil {
+		in, out := &in.BuyNGetOneFree, &out.BuyNGetOneFree
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionDiscount.
+func (in *PromotionDiscount) DeepCopy() *PromotionDiscount {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionDiscount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionList) DeepCopyInto(out *PromotionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Promotion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionList.
+func (in *PromotionList) DeepCopy() *PromotionList {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PromotionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionSelector) DeepCopyInto(out *PromotionSelector) {
+	*out = *in
+	if in.PizzaSelector != nil {
+		in, out := &in.PizzaSelector, &out.PizzaSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Toppings != nil {
+		in, out := &in.Toppings, &out.Toppings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionSelector.
+func (in *PromotionSelector) DeepCopy() *PromotionSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionSpec) DeepCopyInto(out *PromotionSpec) {
+	*out = *in
+	if in.ValidFrom != nil {
+		in, out := &in.ValidFrom, &out.ValidFrom
+		*out = (*in).DeepCopy()
+	}
+	if in.ValidUntil != nil {
+		in, out := &in.ValidUntil, &out.ValidUntil
+		*out = (*in).DeepCopy()
+	}
+	in.Selector.DeepCopyInto(&out.Selector)
+	in.Discount.DeepCopyInto(&out.Discount)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionSpec.
+func (in *PromotionSpec) DeepCopy() *PromotionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecipeStatus) DeepCopyInto(out *RecipeStatus) {
+	*out = *in
+	if in.Toppings != nil {
+		in, out := &in.Toppings, &out.Toppings
+		*out = make([]PizzaTopping, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecipeStatus.
+func (in *RecipeStatus) DeepCopy() *RecipeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RecipeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
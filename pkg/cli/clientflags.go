@@ -0,0 +1,76 @@
+// Package cli contains helpers shared by the command line tools.
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ClientFlags are the standard kubeconfig flags of kubectl, like --kubeconfig,
+// --context, --server and --namespace.
+type ClientFlags struct {
+	loadingRules *clientcmd.ClientConfigLoadingRules
+	overrides    clientcmd.ConfigOverrides
+	config       clientcmd.ClientConfig
+}
+
+func NewClientFlags() *ClientFlags {
+	return &ClientFlags{
+		loadingRules: clientcmd.NewDefaultClientConfigLoadingRules(),
+	}
+}
+
+func (f *ClientFlags) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&f.loadingRules.ExplicitPath, clientcmd.RecommendedConfigPathFlag, f.loadingRules.ExplicitPath,
+		"Path to the kubeconfig file to use for CLI requests.")
+	clientcmd.BindOverrideFlags(&f.overrides, fs, clientcmd.RecommendedConfigOverrideFlags(""))
+}
+
+// ClientConfig returns the kubeconfig with the flag overrides applied.
+func (f *ClientFlags) ClientConfig() clientcmd.ClientConfig {
+	if f.config == nil {
+		f.config = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(f.loadingRules, &f.overrides)
+	}
+	return f.config
+}
+
+// RESTConfig returns the client configuration for the selected context.
+func (f *ClientFlags) RESTConfig() (*rest.Config, error) {
+	config, err := f.ClientConfig().ClientConfig()
+	if err != nil {
+		if clientcmd.IsEmptyConfig(err) {
+			return nil, fmt.Errorf("no kubeconfig found: set --kubeconfig, KUBECONFIG or --server")
+		}
+		return nil, fmt.Errorf("failed to load client configuration: %w", err)
+	}
+	return config, nil
+}
+
+// Namespace returns the namespace from --namespace or the kubeconfig context.
+func (f *ClientFlags) Namespace() (string, error) {
+	namespace, _, err := f.ClientConfig().Namespace()
+	return namespace, err
+}
+
+// LoadClientConfig loads the client configuration from a kubeconfig, falling
+// back to the in-cluster configuration when no kubeconfig is found.
+func LoadClientConfig(kubeconfig, master, context string) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: context,
+	}
+	overrides.ClusterInfo.Server = master
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		if clientcmd.IsEmptyConfig(err) {
+			return nil, fmt.Errorf("no kubeconfig found and not running in a cluster: set --kubeconfig, KUBECONFIG or --master")
+		}
+		return nil, fmt.Errorf("failed to load client configuration: %w", err)
+	}
+	return config, nil
+}
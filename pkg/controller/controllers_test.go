@@ -0,0 +1,77 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/controller/cost"
+	"github.com/zeroisme/pizza-crd/pkg/controller/index"
+	"github.com/zeroisme/pizza-crd/pkg/controller/lifecycle"
+	"github.com/zeroisme/pizza-crd/pkg/controller/order"
+	"github.com/zeroisme/pizza-crd/pkg/controller/recipe"
+	"github.com/zeroisme/pizza-crd/pkg/controller/stock"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned/fake"
+	restaurantinformers "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestSharedInformerFactory builds all controllers on one informer factory,
+// like cmd/pizza-crd-controller does, so that their indexes on the shared
+// informers must not conflict.
+func TestSharedInformerFactory(t *testing.T) {
+	pizza := &v1alpha1.Pizza{
+		ObjectMeta: metav1.ObjectMeta{Name: "margherita", Namespace: "default"},
+		Spec:       v1alpha1.PizzaSpec{Toppings: []string{"tomato", "mozzarella"}},
+	}
+	clientset := fake.NewSimpleClientset(pizza)
+	informers := restaurantinformers.NewSharedInformerFactory(clientset, 0)
+
+	for name, newController := range map[string]func(versioned.Interface, restaurantinformers.SharedInformerFactory, string) error{
+		cost.ControllerName: func(c versioned.Interface, i restaurantinformers.SharedInformerFactory, m string) error {
+			_, err := cost.NewController(c, i, m)
+			return err
+		},
+		order.ControllerName: func(c versioned.Interface, i restaurantinformers.SharedInformerFactory, m string) error {
+			_, err := order.NewController(c, i, m)
+			return err
+		},
+		stock.ControllerName: func(c versioned.Interface, i restaurantinformers.SharedInformerFactory, m string) error {
+			_, err := stock.NewController(c, i, m)
+			return err
+		},
+		lifecycle.ControllerName: func(c versioned.Interface, i restaurantinformers.SharedInformerFactory, m string) error {
+			_, err := lifecycle.NewController(c, i, m)
+			return err
+		},
+		recipe.ControllerName: func(c versioned.Interface, i restaurantinformers.SharedInformerFactory, m string) error {
+			_, err := recipe.NewController(c, i, m)
+			return err
+		},
+	} {
+		if err := newController(clientset, informers, name); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	informers.Start(ctx.Done())
+	defer func() {
+		cancel()
+		informers.Shutdown()
+	}()
+	for typ, synced := range informers.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			t.Fatalf("informer for %v did not sync", typ)
+		}
+	}
+
+	pizzas, err := informers.Restaurant().V1alpha1().Pizzas().Informer().GetIndexer().ByIndex(index.PizzaTopping, "mozzarella")
+	if err != nil {
+		t.Fatalf("failed to look up pizzas by topping: %v", err)
+	}
+	if len(pizzas) != 1 {
+		t.Errorf("expected 1 pizza with mozzarella, got %d", len(pizzas))
+	}
+}
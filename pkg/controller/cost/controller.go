@@ -0,0 +1,299 @@
+// Package cost implements the controller which keeps status.cost,
+// status.costBreakdown, status.allergens and status.nutrition of Pizzas in
+// sync with the prices, labels and nutrition data of their toppings and with
+// the promotions selecting them.
+package cost
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	"github.com/zeroisme/pizza-crd/pkg/controller/index"
+	applyv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/applyconfiguration/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	restaurantinformers "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1alpha1"
+	restaurantv1beta2 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1beta2"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+// ControllerName is the name of the controller in logs and the default field
+// manager of the status it applies.
+const ControllerName = "pizza-cost-controller"
+
+// ConditionNutritionComplete is the type of the Pizza condition which tells
+// whether all toppings have nutrition data, i.e. whether status.nutrition is
+// set.
+const ConditionNutritionComplete = "NutritionComplete"
+
+// Controller applies status.cost, status.costBreakdown, status.allergens,
+// status.nutrition and the NutritionComplete condition of Pizzas, owning only
+// these fields, so that status fields written by other managers are left
+// alone.
+type Controller struct {
+	clientset       versioned.Interface
+	pizzaLister     restaurantv1alpha1.PizzaLister
+	pizzaIndexer    cache.Indexer
+	toppingLister   restaurantv1alpha1.ToppingLister
+	promotionLister restaurantv1beta2.PromotionLister
+	synced          []cache.InformerSynced
+	queue           workqueue.RateLimitingInterface
+	fieldManager    string
+	clock           clock.Clock
+}
+
+// NewController creates a cost controller. The informers have to be started
+// after the controller was created.
+func NewController(clientset versioned.Interface, informers restaurantinformers.SharedInformerFactory, fieldManager string) (*Controller, error) {
+	pizzaInformer := informers.Restaurant().V1alpha1().Pizzas()
+	toppingInformer := informers.Restaurant().V1alpha1().Toppings()
+	promotionInformer := informers.Restaurant().V1beta2().Promotions()
+	if err := index.AddPizzaTopping(pizzaInformer.Informer()); err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		clientset:       clientset,
+		pizzaLister:     pizzaInformer.Lister(),
+		pizzaIndexer:    pizzaInformer.Informer().GetIndexer(),
+		toppingLister:   toppingInformer.Lister(),
+		promotionLister: promotionInformer.Lister(),
+		synced: []cache.InformerSynced{
+			pizzaInformer.Informer().HasSynced,
+			toppingInformer.Informer().HasSynced,
+			promotionInformer.Informer().HasSynced,
+		},
+		queue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+		fieldManager: fieldManager,
+		clock:        clock.RealClock{},
+	}
+
+	if _, err := pizzaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueuePizza,
+		UpdateFunc: func(_, obj interface{}) { c.enqueuePizza(obj) },
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := toppingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueToppingPizzas,
+		UpdateFunc: func(old, obj interface{}) {
+			oldSpec, spec := old.(*v1alpha1.Topping).Spec, obj.(*v1alpha1.Topping).Spec
+			if oldSpec.Cost != spec.Cost || !apiequality.Semantic.DeepEqual(oldSpec.Sizes, spec.Sizes) ||
+				!apiequality.Semantic.DeepEqual(oldSpec.Allergens, spec.Allergens) ||
+				!apiequality.Semantic.DeepEqual(oldSpec.Nutrition, spec.Nutrition) {
+				c.enqueueToppingPizzas(obj)
+			}
+		},
+		DeleteFunc: c.enqueueToppingPizzas,
+	}); err != nil {
+		return nil, err
+	}
+	// a promotion may select any pizza, also through a changed selector
+	if _, err := promotionInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(interface{}) { c.enqueueAllPizzas() },
+		UpdateFunc: func(old, obj interface{}) {
+			if old.(*v1beta2.Promotion).Generation != obj.(*v1beta2.Promotion).Generation {
+				c.enqueueAllPizzas()
+			}
+		},
+		DeleteFunc: func(interface{}) { c.enqueueAllPizzas() },
+	}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Controller) enqueuePizza(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// enqueueToppingPizzas enqueues the Pizzas with a changed topping.
+func (c *Controller) enqueueToppingPizzas(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	topping, ok := obj.(*v1alpha1.Topping)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("unexpected object %T", obj))
+		return
+	}
+	pizzas, err := c.pizzaIndexer.ByIndex(index.PizzaTopping, topping.Name)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, pizza := range pizzas {
+		c.enqueuePizza(pizza)
+	}
+}
+
+func (c *Controller) enqueueAllPizzas() {
+	pizzas, err := c.pizzaLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, pizza := range pizzas {
+		c.enqueuePizza(pizza)
+	}
+}
+
+// Run processes Pizzas with the given number of workers until ctx is done.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithName(ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller", "workers", workers)
+	defer logger.Info("Shutting down controller")
+
+	if !cache.WaitForNamedCacheSync(ControllerName, ctx.Done(), c.synced...) {
+		return
+	}
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(ctx, key.(string)); err != nil {
+		utilruntime.HandleError(fmt.Errorf("syncing pizza %q failed: %w", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) sync(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	pizza, err := c.pizzaLister.Pizzas(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	logger := klog.FromContext(ctx).WithValues("pizza", klog.KObj(pizza))
+
+	toppings, err := Resolve(pizza, c.toppingLister)
+	if apierrors.IsNotFound(err) {
+		// retried when the topping is created
+		logger.V(2).Info("Not computing cost", "reason", err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	promotions, err := c.promotionLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	promotions = toppings.Selected(pizza, promotions)
+	now := c.clock.Now()
+	// the cost changes when a promotion starts or ends
+	for _, p := range promotions {
+		if next, ok := p.NextTransition(now); ok {
+			c.queue.AddAfter(key, next.Sub(now))
+		}
+	}
+	breakdown := toppings.Price(promotions, now)
+	cost := breakdown.Final
+	allergens := toppings.Allergens()
+	nutrition, missing := toppings.Nutrition()
+	condition := metav1.Condition{
+		Type:               ConditionNutritionComplete,
+		Status:             metav1.ConditionTrue,
+		Reason:             "NutritionComplete",
+		Message:            "all toppings have nutrition data",
+		ObservedGeneration: pizza.Generation,
+	}
+	if len(missing) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NutritionMissing"
+		condition.Message = fmt.Sprintf("toppings without nutrition data: %s", strings.Join(missing, ", "))
+	}
+	existing := meta.FindStatusCondition(pizza.Status.Conditions, ConditionNutritionComplete)
+	if pizza.Status.Cost == cost &&
+		apiequality.Semantic.DeepEqual(pizza.Status.CostBreakdown, breakdown) &&
+		apiequality.Semantic.DeepEqual(pizza.Status.Allergens, allergens) &&
+		apiequality.Semantic.DeepEqual(pizza.Status.Nutrition, nutrition) &&
+		existing != nil && existing.Status == condition.Status && existing.Message == condition.Message &&
+		existing.ObservedGeneration == condition.ObservedGeneration {
+		return nil
+	}
+	condition.LastTransitionTime = metav1.Now()
+	if existing != nil && existing.Status == condition.Status {
+		condition.LastTransitionTime = existing.LastTransitionTime
+	}
+
+	// every apply contains all fields of the controller, because fields left
+	// out would be removed
+	logger.V(2).Info("Applying cost", "old", pizza.Status.Cost, "new", cost, "allergens", allergens, "nutritionComplete", condition.Status)
+	breakdownApply := applyv1alpha1.CostBreakdown().WithBase(breakdown.Base).WithFinal(breakdown.Final)
+	for _, d := range breakdown.Discounts {
+		breakdownApply.WithDiscounts(applyv1alpha1.AppliedDiscount().WithPromotion(d.Promotion).WithAmount(d.Amount))
+	}
+	statusApply := applyv1alpha1.PizzaStatus().WithCost(cost).WithCostBreakdown(breakdownApply).WithAllergens(allergens...).WithConditions(condition)
+	if nutrition != nil {
+		statusApply.WithNutrition(applyv1alpha1.Nutrition().
+			WithCalories(nutrition.Calories).
+			WithProtein(nutrition.Protein).
+			WithFat(nutrition.Fat).
+			WithSalt(nutrition.Salt))
+	}
+	status := applyv1alpha1.Pizza(name, namespace).WithStatus(statusApply)
+	_, err = c.clientset.RestaurantV1alpha1().Pizzas(namespace).ApplyStatus(ctx, status, metav1.ApplyOptions{FieldManager: c.fieldManager, Force: true})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// Cost returns the cost of all toppings of a Pizza with the discounts of the
+// promotions valid at now. It returns a NotFound error if a topping does not
+// exist.
+func Cost(pizza *v1alpha1.Pizza, toppingLister restaurantv1alpha1.ToppingLister, promotionLister restaurantv1beta2.PromotionLister, now time.Time) (float64, error) {
+	toppings, err := Resolve(pizza, toppingLister)
+	if err != nil {
+		return 0, err
+	}
+	promotions, err := promotionLister.List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+	return toppings.Price(toppings.Selected(pizza, promotions), now).Final, nil
+}
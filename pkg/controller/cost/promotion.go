@@ -0,0 +1,92 @@
+package cost
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Selected returns the promotions selecting a pizza with the toppings,
+// whether they are valid or not, in the order their discounts are applied.
+func (ts Toppings) Selected(pizza metav1.Object, promotions []*v1beta2.Promotion) []*v1beta2.Promotion {
+	names := map[string]bool{}
+	for _, t := range ts {
+		names[t.Topping.Name] = true
+	}
+	hasTopping := func(name string) bool { return names[name] }
+
+	var selected []*v1beta2.Promotion
+	for _, p := range promotions {
+		if p.Spec.Selector.Matches(pizza.GetNamespace(), pizza.GetLabels(), hasTopping) {
+			selected = append(selected, p)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Less(selected[j]) })
+	return selected
+}
+
+// Price returns the cost of the toppings with the discounts of the promotions
+// valid at now applied in the given order. Discounts which take nothing off
+// are left out.
+func (ts Toppings) Price(promotions []*v1beta2.Promotion, now time.Time) *v1alpha1.CostBreakdown {
+	base := ts.Cost()
+	breakdown := &v1alpha1.CostBreakdown{Base: base, Final: base}
+	for _, p := range promotions {
+		if !p.ValidAt(now) {
+			continue
+		}
+		amount := math.Min(ts.discount(p, breakdown.Final), breakdown.Final)
+		if amount <= 0 {
+			continue
+		}
+		breakdown.Discounts = append(breakdown.Discounts, v1alpha1.AppliedDiscount{Promotion: p.Name, Amount: amount})
+		breakdown.Final -= amount
+	}
+	return breakdown
+}
+
+// discount returns the amount the promotion takes off the cost left by the
+// promotions applied before.
+func (ts Toppings) discount(p *v1beta2.Promotion, cost float64) float64 {
+	d := p.Spec.Discount
+	switch {
+	case d.BuyNGetOneFree != nil:
+		return ts.freeToppings(p.Spec.Selector.Toppings, int(*d.BuyNGetOneFree))
+	case d.PercentOff != nil:
+		return cost * *d.PercentOff / 100
+	case d.AmountOff != nil:
+		return *d.AmountOff
+	}
+	return 0
+}
+
+// freeToppings returns the cost of one topping for every n toppings paid for,
+// the cheapest ones first. Only the given toppings count if there are any. The
+// toppings of a section cost their share of the unit cost.
+func (ts Toppings) freeToppings(only []string, n int) float64 {
+	counted := map[string]bool{}
+	for _, name := range only {
+		counted[name] = true
+	}
+	var units []float64
+	for _, t := range ts {
+		if t.Quantity == 0 || (len(counted) > 0 && !counted[t.Topping.Name]) {
+			continue
+		}
+		cost := t.UnitCost * t.Portions / float64(t.Quantity)
+		for i := int64(0); i < t.Quantity; i++ {
+			units = append(units, cost)
+		}
+	}
+	sort.Float64s(units)
+
+	var free float64
+	for _, cost := range units[:len(units)/(n+1)] {
+		free += cost
+	}
+	return free
+}
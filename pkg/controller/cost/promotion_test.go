@@ -0,0 +1,196 @@
+package cost
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var now = time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func percentOff(name string, percent float64) *v1beta2.Promotion {
+	return &v1beta2.Promotion{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1beta2.PromotionSpec{Discount: v1beta2.PromotionDiscount{PercentOff: &percent}},
+	}
+}
+
+func amountOff(name string, amount float64) *v1beta2.Promotion {
+	return &v1beta2.Promotion{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1beta2.PromotionSpec{Discount: v1beta2.PromotionDiscount{AmountOff: &amount}},
+	}
+}
+
+func buyNGetOneFree(name string, n int32, toppings ...string) *v1beta2.Promotion {
+	return &v1beta2.Promotion{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1beta2.PromotionSpec{
+			Selector: v1beta2.PromotionSelector{Toppings: toppings},
+			Discount: v1beta2.PromotionDiscount{BuyNGetOneFree: &n},
+		},
+	}
+}
+
+func validFrom(p *v1beta2.Promotion, from time.Time) *v1beta2.Promotion {
+	p.Spec.ValidFrom = &metav1.Time{Time: from}
+	return p
+}
+
+func validUntil(p *v1beta2.Promotion, until time.Time) *v1beta2.Promotion {
+	p.Spec.ValidUntil = &metav1.Time{Time: until}
+	return p
+}
+
+// resolved returns a topping on the whole pizza.
+func resolved(name string, quantity int64, unitCost float64) ResolvedTopping {
+	return ResolvedTopping{
+		Topping:  &v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: name}},
+		Quantity: quantity,
+		Portions: float64(quantity),
+		UnitCost: unitCost,
+	}
+}
+
+func TestPrice(t *testing.T) {
+	// base cost 10
+	toppings := Toppings{resolved("tomato", 2, 1), resolved("salami", 2, 2), resolved("olives", 4, 1)}
+
+	tests := []struct {
+		name       string
+		toppings   Toppings
+		promotions []*v1beta2.Promotion
+		want       *v1alpha1.CostBreakdown
+	}{
+		{
+			name:     "no promotions",
+			toppings: toppings,
+			want:     &v1alpha1.CostBreakdown{Base: 10, Final: 10},
+		},
+		{
+			name:       "percent off before amount off",
+			toppings:   toppings,
+			promotions: []*v1beta2.Promotion{percentOff("a", 10), amountOff("b", 2)},
+			want: &v1alpha1.CostBreakdown{Base: 10, Final: 7, Discounts: []v1alpha1.AppliedDiscount{
+				{Promotion: "a", Amount: 1}, {Promotion: "b", Amount: 2},
+			}},
+		},
+		{
+			name:       "percent off of the cost left in the given order",
+			toppings:   toppings,
+			promotions: []*v1beta2.Promotion{amountOff("a", 2), percentOff("b", 50)},
+			want: &v1alpha1.CostBreakdown{Base: 10, Final: 4, Discounts: []v1alpha1.AppliedDiscount{
+				{Promotion: "a", Amount: 2}, {Promotion: "b", Amount: 4},
+			}},
+		},
+		{
+			name:       "amount off capped at the cost left",
+			toppings:   toppings,
+			promotions: []*v1beta2.Promotion{amountOff("a", 8), amountOff("b", 5), amountOff("c", 1)},
+			want: &v1alpha1.CostBreakdown{Base: 10, Final: 0, Discounts: []v1alpha1.AppliedDiscount{
+				{Promotion: "a", Amount: 8}, {Promotion: "b", Amount: 2},
+			}},
+		},
+		{
+			name:       "buy two get one free of eight units",
+			toppings:   toppings,
+			promotions: []*v1beta2.Promotion{buyNGetOneFree("a", 2)},
+			// two of the six cheapest units at 1 are free
+			want: &v1alpha1.CostBreakdown{Base: 10, Final: 8, Discounts: []v1alpha1.AppliedDiscount{
+				{Promotion: "a", Amount: 2},
+			}},
+		},
+		{
+			name:       "buy three get one free rounded down",
+			toppings:   Toppings{resolved("salami", 3, 2), resolved("tomato", 4, 1)},
+			promotions: []*v1beta2.Promotion{buyNGetOneFree("a", 3, "salami")},
+			// three salami units are fewer than four
+			want: &v1alpha1.CostBreakdown{Base: 10, Final: 10},
+		},
+		{
+			name:       "buy one get one free of the selected toppings",
+			toppings:   toppings,
+			promotions: []*v1beta2.Promotion{buyNGetOneFree("a", 1, "salami", "olives")},
+			// three of the six salami and olive units, the olives at 1
+			want: &v1alpha1.CostBreakdown{Base: 10, Final: 7, Discounts: []v1alpha1.AppliedDiscount{
+				{Promotion: "a", Amount: 3},
+			}},
+		},
+		{
+			name: "free toppings of sections pro-rated",
+			toppings: Toppings{
+				resolved("tomato", 1, 1),
+				// two units of salami on a half cost 2 * 0.5 each
+				{Topping: &v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "salami"}}, Quantity: 2, Portions: 1, UnitCost: 2},
+			},
+			promotions: []*v1beta2.Promotion{buyNGetOneFree("a", 1, "salami")},
+			want: &v1alpha1.CostBreakdown{Base: 3, Final: 2, Discounts: []v1alpha1.AppliedDiscount{
+				{Promotion: "a", Amount: 1},
+			}},
+		},
+		{
+			name:     "validity window",
+			toppings: toppings,
+			promotions: []*v1beta2.Promotion{
+				validFrom(amountOff("not-yet", 1), now.Add(time.Second)),
+				validFrom(amountOff("starts-now", 1), now),
+				validUntil(amountOff("ends-now", 1), now),
+				validUntil(amountOff("ends-later", 1), now.Add(time.Second)),
+			},
+			want: &v1alpha1.CostBreakdown{Base: 10, Final: 8, Discounts: []v1alpha1.AppliedDiscount{
+				{Promotion: "starts-now", Amount: 1}, {Promotion: "ends-later", Amount: 1},
+			}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.toppings.Price(test.promotions, now)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("unexpected cost breakdown (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSelected(t *testing.T) {
+	toppings := Toppings{resolved("tomato", 1, 1), resolved("salami", 1, 2)}
+	pizza := &metav1.ObjectMeta{Namespace: "lunch", Name: "salami", Labels: map[string]string{"menu": "classics"}}
+
+	inNamespaces := amountOff("in-namespaces", 1)
+	inNamespaces.Spec.Selector.Namespaces = []string{"dinner", "lunch"}
+	inOtherNamespace := amountOff("in-other-namespace", 1)
+	inOtherNamespace.Spec.Selector.Namespaces = []string{"dinner"}
+	classics := percentOff("classics", 10)
+	classics.Spec.Selector.PizzaSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"menu": "classics"}}
+	specials := percentOff("specials", 10)
+	specials.Spec.Selector.PizzaSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"menu": "specials"}}
+	invalidSelector := percentOff("invalid-selector", 10)
+	invalidSelector.Spec.Selector.PizzaSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "menu", Operator: "Unknown"}}}
+
+	got := toppings.Selected(pizza, []*v1beta2.Promotion{
+		amountOff("z-everything", 1),
+		inNamespaces,
+		inOtherNamespace,
+		classics,
+		specials,
+		invalidSelector,
+		percentOff("a-everything", 10),
+		buyNGetOneFree("salami", 1, "salami"),
+		buyNGetOneFree("olives", 1, "olives"),
+		validUntil(amountOff("expired", 1), now.Add(-time.Hour)),
+	})
+	var names []string
+	for _, p := range got {
+		names = append(names, p.Name)
+	}
+	// buyNGetOneFree first, then percentOff and amountOff, each by name;
+	// promotions out of their validity window are selected as well
+	want := []string{"salami", "a-everything", "classics", "expired", "in-namespaces", "z-everything"}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Errorf("unexpected promotions (-want +got):\n%s", diff)
+	}
+}
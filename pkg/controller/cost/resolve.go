@@ -0,0 +1,126 @@
+package cost
+
+import (
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/webhook/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ResolvedTopping is a Topping of a pizza with the number of times it is put
+// onto the pizza and its cost for the size of the pizza.
+type ResolvedTopping struct {
+	Topping  *v1alpha1.Topping
+	Quantity int64
+	// Portions is the quantity with toppings of sections pro-rated by the
+	// coverage of the section.
+	Portions float64
+	UnitCost float64
+}
+
+// Toppings are the resolved toppings of a pizza. The cost, allergens and
+// nutrition of a pizza are all computed from them, so that they always agree
+// on the toppings and their quantities.
+type Toppings []ResolvedTopping
+
+// Resolve looks up the toppings of a Pizza of any version, in the order they
+// first appear on the pizza. The pizza is converted to v1beta2 first, so the
+// quantity of a topping is the number of its duplicates in v1alpha1 and the
+// sum of its quantities in later versions, and the size and sections are
+// taken from their annotations in the versions without them. The toppings
+// resolved from the recipe of the pizza are on the whole pizza. It returns a
+// NotFound error if a topping does not exist.
+func Resolve(pizzaObj runtime.Object, toppingLister restaurantv1alpha1.ToppingLister) (Toppings, error) {
+	hubObj, err := conversion.Convert(pizzaObj, v1beta2.SchemeGroupVersion.String())
+	if err != nil {
+		return nil, err
+	}
+	pizza := hubObj.(*v1beta2.Pizza)
+
+	size := v1alpha1.DefaultPizzaSize
+	if len(pizza.Spec.Size) > 0 {
+		size = v1alpha1.PizzaSize(pizza.Spec.Size)
+	}
+	var names []string
+	quantities := map[string]int64{}
+	portions := map[string]float64{}
+	add := func(t v1beta2.PizzaTopping, coverage float64) {
+		if _, ok := quantities[t.Name]; !ok {
+			names = append(names, t.Name)
+		}
+		quantities[t.Name] += int64(t.Quantity)
+		portions[t.Name] += float64(t.Quantity) * coverage
+	}
+	for _, t := range pizza.Spec.Toppings {
+		add(t, 1)
+	}
+	if pizza.Status.Recipe != nil {
+		for _, t := range pizza.Status.Recipe.Toppings {
+			add(t, 1)
+		}
+	}
+	for _, section := range pizza.Spec.Sections {
+		for _, t := range section.Toppings {
+			add(t, section.Coverage)
+		}
+	}
+
+	toppings := make(Toppings, 0, len(names))
+	for _, name := range names {
+		topping, err := toppingLister.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		toppings = append(toppings, ResolvedTopping{
+			Topping:  topping,
+			Quantity: quantities[name],
+			Portions: portions[name],
+			UnitCost: topping.CostFor(size),
+		})
+	}
+	return toppings, nil
+}
+
+// Cost returns the cost of all toppings.
+func (ts Toppings) Cost() float64 {
+	var cost float64
+	for _, t := range ts {
+		cost += t.UnitCost * t.Portions
+	}
+	return cost
+}
+
+// Allergens returns the allergens of all toppings in alphabetical order.
+func (ts Toppings) Allergens() []v1alpha1.Allergen {
+	allergens := sets.New[v1alpha1.Allergen]()
+	for _, t := range ts {
+		allergens.Insert(t.Topping.Spec.Allergens...)
+	}
+	return sets.List(allergens)
+}
+
+// Nutrition returns the nutrition of all toppings, and the names of the
+// toppings without nutrition data. Toppings of sections are pro-rated by their
+// coverage. The nutrition is nil if any is missing.
+func (ts Toppings) Nutrition() (*v1alpha1.Nutrition, []string) {
+	var missing []string
+	total := &v1alpha1.Nutrition{}
+	for _, t := range ts {
+		n := t.Topping.Spec.Nutrition
+		if n == nil {
+			missing = append(missing, t.Topping.Name)
+			continue
+		}
+		q := t.Portions
+		total.Calories += n.Calories * q
+		total.Protein += n.Protein * q
+		total.Fat += n.Fat * q
+		total.Salt += n.Salt * q
+	}
+	if len(missing) > 0 {
+		return nil, missing
+	}
+	return total, nil
+}
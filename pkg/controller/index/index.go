@@ -0,0 +1,33 @@
+// Package index implements the indexes several controllers share on the
+// informers of one SharedInformerFactory.
+package index
+
+import (
+	"fmt"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+)
+
+// PizzaTopping indexes Pizzas by the names of their toppings, including the
+// toppings resolved from their recipe.
+const PizzaTopping = "topping"
+
+// AddPizzaTopping adds the PizzaTopping index to a Pizza informer. The index
+// may exist already, because every controller using it adds it to the shared
+// informer.
+func AddPizzaTopping(informer cache.SharedIndexInformer) error {
+	if _, ok := informer.GetIndexer().GetIndexers()[PizzaTopping]; ok {
+		return nil
+	}
+	return informer.AddIndexers(cache.Indexers{PizzaTopping: pizzaToppings})
+}
+
+func pizzaToppings(obj interface{}) ([]string, error) {
+	pizza, ok := obj.(*v1alpha1.Pizza)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object %T", obj)
+	}
+	return sets.List(sets.New(pizza.AllToppings()...)), nil
+}
@@ -0,0 +1,186 @@
+// Package lifecycle implements the controller which retires Toppings at their
+// sunset time and reports the Pizzas still using deprecated or retired
+// toppings.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/controller/index"
+	applyv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/applyconfiguration/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	restaurantinformers "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1alpha1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+// ControllerName is the name of the controller in logs and the default field
+// manager of the status it applies.
+const ControllerName = "pizza-lifecycle-controller"
+
+// Controller applies status.phase and status.affectedPizzas of Toppings. A
+// topping is requeued for its sunset time, so that it is reported as Retired
+// when the time passes.
+type Controller struct {
+	clientset     versioned.Interface
+	toppingLister restaurantv1alpha1.ToppingLister
+	pizzaIndexer  cache.Indexer
+	synced        []cache.InformerSynced
+	queue         workqueue.RateLimitingInterface
+	fieldManager  string
+	clock         clock.Clock
+}
+
+// NewController creates a lifecycle controller. The informers have to be
+// started after the controller was created.
+func NewController(clientset versioned.Interface, informers restaurantinformers.SharedInformerFactory, fieldManager string) (*Controller, error) {
+	toppingInformer := informers.Restaurant().V1alpha1().Toppings()
+	pizzaInformer := informers.Restaurant().V1alpha1().Pizzas()
+	if err := index.AddPizzaTopping(pizzaInformer.Informer()); err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		clientset:     clientset,
+		toppingLister: toppingInformer.Lister(),
+		pizzaIndexer:  pizzaInformer.Informer().GetIndexer(),
+		synced:        []cache.InformerSynced{toppingInformer.Informer().HasSynced, pizzaInformer.Informer().HasSynced},
+		queue:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+		fieldManager:  fieldManager,
+		clock:         clock.RealClock{},
+	}
+
+	if _, err := toppingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueTopping,
+		UpdateFunc: func(_, obj interface{}) { c.enqueueTopping(obj) },
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := pizzaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueuePizzaToppings,
+		UpdateFunc: func(old, obj interface{}) {
+			c.enqueuePizzaToppings(old)
+			c.enqueuePizzaToppings(obj)
+		},
+		DeleteFunc: c.enqueuePizzaToppings,
+	}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Controller) enqueueTopping(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// enqueuePizzaToppings enqueues the toppings of a changed pizza.
+func (c *Controller) enqueuePizzaToppings(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pizza, ok := obj.(*v1alpha1.Pizza)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("unexpected object %T", obj))
+		return
+	}
+	for _, name := range pizza.AllToppings() {
+		c.queue.Add(name)
+	}
+}
+
+// Run processes Toppings with the given number of workers until ctx is done.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithName(ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller", "workers", workers)
+	defer logger.Info("Shutting down controller")
+
+	if !cache.WaitForNamedCacheSync(ControllerName, ctx.Done(), c.synced...) {
+		return
+	}
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(ctx, key.(string)); err != nil {
+		utilruntime.HandleError(fmt.Errorf("syncing topping %q failed: %w", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) sync(ctx context.Context, name string) error {
+	topping, err := c.toppingLister.Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	logger := klog.FromContext(ctx).WithValues("topping", klog.KObj(topping))
+
+	now := c.clock.Now()
+	phase := topping.EffectivePhase(now)
+	if phase != v1alpha1.ToppingRetired && topping.Spec.SunsetTime != nil {
+		c.queue.AddAfter(name, topping.Spec.SunsetTime.Sub(now))
+	}
+
+	var affected []string
+	if phase != v1alpha1.ToppingActive {
+		pizzas, err := c.pizzaIndexer.ByIndex(index.PizzaTopping, name)
+		if err != nil {
+			return err
+		}
+		for _, obj := range pizzas {
+			pizza := obj.(*v1alpha1.Pizza)
+			affected = append(affected, pizza.Namespace+"/"+pizza.Name)
+		}
+		sort.Strings(affected)
+	}
+	if topping.Status.Phase == phase && apiequality.Semantic.DeepEqual(topping.Status.AffectedPizzas, affected) {
+		return nil
+	}
+
+	logger.V(2).Info("Applying lifecycle status", "oldPhase", topping.Status.Phase, "phase", phase, "affectedPizzas", len(affected))
+	status := applyv1alpha1.ToppingStatus().WithPhase(phase).WithAffectedPizzas(affected...)
+	_, err = c.clientset.RestaurantV1alpha1().Toppings().ApplyStatus(ctx, applyv1alpha1.Topping(name).WithStatus(status), metav1.ApplyOptions{FieldManager: c.fieldManager, Force: true})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,280 @@
+// Package order implements the controller which moves Orders through their
+// phases and computes their totals.
+package order
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/controller/cost"
+	applyv1beta1 "github.com/zeroisme/pizza-crd/pkg/generated/applyconfiguration/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	restaurantinformers "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1alpha1"
+	restaurantv1beta1 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1beta1"
+	restaurantv1beta2 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1beta2"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+// ControllerName is the name of the controller in logs and the default field
+// manager of the status it applies.
+const ControllerName = "pizza-order-controller"
+
+// pizzaIndex indexes Orders by the namespaced names of their pizzas.
+const pizzaIndex = "pizza"
+
+// Controller moves Orders to the phase requested in their spec, one legal
+// transition at a time, and records when each phase was entered. While an
+// order is Pending its total follows the cost of its pizzas.
+type Controller struct {
+	clientset       versioned.Interface
+	orderLister     restaurantv1beta1.OrderLister
+	orderIndexer    cache.Indexer
+	pizzaLister     restaurantv1alpha1.PizzaLister
+	toppingLister   restaurantv1alpha1.ToppingLister
+	promotionLister restaurantv1beta2.PromotionLister
+	synced          []cache.InformerSynced
+	queue           workqueue.RateLimitingInterface
+	fieldManager    string
+	clock           clock.Clock
+}
+
+// NewController creates an order controller. The informers have to be started
+// after the controller was created.
+func NewController(clientset versioned.Interface, informers restaurantinformers.SharedInformerFactory, fieldManager string) (*Controller, error) {
+	orderInformer := informers.Restaurant().V1beta1().Orders()
+	pizzaInformer := informers.Restaurant().V1alpha1().Pizzas()
+	toppingInformer := informers.Restaurant().V1alpha1().Toppings()
+	promotionInformer := informers.Restaurant().V1beta2().Promotions()
+	if err := orderInformer.Informer().AddIndexers(cache.Indexers{pizzaIndex: indexByPizza}); err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		clientset:       clientset,
+		orderLister:     orderInformer.Lister(),
+		orderIndexer:    orderInformer.Informer().GetIndexer(),
+		pizzaLister:     pizzaInformer.Lister(),
+		toppingLister:   toppingInformer.Lister(),
+		promotionLister: promotionInformer.Lister(),
+		synced: []cache.InformerSynced{
+			orderInformer.Informer().HasSynced,
+			pizzaInformer.Informer().HasSynced,
+			toppingInformer.Informer().HasSynced,
+			promotionInformer.Informer().HasSynced,
+		},
+		queue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+		fieldManager: fieldManager,
+		clock:        clock.RealClock{},
+	}
+
+	if _, err := orderInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueOrder,
+		UpdateFunc: func(_, obj interface{}) { c.enqueueOrder(obj) },
+	}); err != nil {
+		return nil, err
+	}
+	// pizzas change when their toppings or promotions change, because the
+	// cost controller updates their status
+	if _, err := pizzaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueuePizzaOrders,
+		UpdateFunc: func(_, obj interface{}) { c.enqueuePizzaOrders(obj) },
+		DeleteFunc: c.enqueuePizzaOrders,
+	}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func indexByPizza(obj interface{}) ([]string, error) {
+	order, ok := obj.(*v1beta1.Order)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object %T", obj)
+	}
+	keys := make([]string, 0, len(order.Spec.Pizzas))
+	for _, item := range order.Spec.Pizzas {
+		keys = append(keys, order.Namespace+"/"+item.Name)
+	}
+	return keys, nil
+}
+
+func (c *Controller) enqueueOrder(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// enqueuePizzaOrders enqueues the Orders of a changed pizza.
+func (c *Controller) enqueuePizzaOrders(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	orders, err := c.orderIndexer.ByIndex(pizzaIndex, key)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, order := range orders {
+		c.enqueueOrder(order)
+	}
+}
+
+// Run processes Orders with the given number of workers until ctx is done.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithName(ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller", "workers", workers)
+	defer logger.Info("Shutting down controller")
+
+	if !cache.WaitForNamedCacheSync(ControllerName, ctx.Done(), c.synced...) {
+		return
+	}
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(ctx, key.(string)); err != nil {
+		utilruntime.HandleError(fmt.Errorf("syncing order %q failed: %w", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) sync(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	order, err := c.orderLister.Orders(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if order.DeletionTimestamp != nil {
+		return nil
+	}
+	logger := klog.FromContext(ctx).WithValues("order", klog.KObj(order))
+
+	status := c.nextStatus(order)
+	if apiequality.Semantic.DeepEqual(status, &order.Status) {
+		return nil
+	}
+
+	logger.V(2).Info("Applying status", "phase", status.Phase, "total", status.Total, "message", status.Message)
+	apply := applyv1beta1.OrderStatus().
+		WithPhase(status.Phase).
+		WithTotal(status.Total).
+		WithObservedGeneration(status.ObservedGeneration)
+	for _, t := range status.PhaseTimes {
+		apply.WithPhaseTimes(applyv1beta1.OrderPhaseTime().WithPhase(t.Phase).WithTime(t.Time))
+	}
+	if len(status.Message) > 0 {
+		apply.WithMessage(status.Message)
+	}
+	_, err = c.clientset.RestaurantV1beta1().Orders(namespace).ApplyStatus(ctx,
+		applyv1beta1.Order(name, namespace).WithStatus(apply),
+		metav1.ApplyOptions{FieldManager: c.fieldManager, Force: true})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// nextStatus returns the status of order after one step towards the requested
+// phase. The total is fixed when the order leaves Pending; an order without
+// total cannot leave Pending other than to Cancelled.
+func (c *Controller) nextStatus(order *v1beta1.Order) *v1beta1.OrderStatus {
+	status := order.Status.DeepCopy()
+	status.ObservedGeneration = order.Generation
+	status.Message = ""
+	now := metav1.NewTime(c.clock.Now())
+	if len(status.Phase) == 0 {
+		status.Phase = v1beta1.OrderPending
+		status.PhaseTimes = append(status.PhaseTimes, v1beta1.OrderPhaseTime{Phase: v1beta1.OrderPending, Time: now})
+	}
+
+	target := order.Spec.Phase.Normalized()
+	if status.Phase == v1beta1.OrderPending {
+		total, err := c.total(order)
+		if err != nil {
+			status.Message = err.Error()
+			if target != v1beta1.OrderCancelled {
+				return status
+			}
+		} else {
+			status.Total = total
+		}
+	}
+
+	if status.Phase == target {
+		return status
+	}
+	if !status.Phase.CanTransitionTo(target) {
+		status.Message = fmt.Sprintf("cannot move from %s to %s", status.Phase, target)
+		return status
+	}
+	status.Phase = target
+	status.PhaseTimes = append(status.PhaseTimes, v1beta1.OrderPhaseTime{Phase: target, Time: now})
+	return status
+}
+
+// total returns the cost of all pizzas of order from their current toppings
+// and the promotions valid now.
+func (c *Controller) total(order *v1beta1.Order) (float64, error) {
+	var total float64
+	var missing []string
+	for _, item := range order.Spec.Pizzas {
+		pizza, err := c.pizzaLister.Pizzas(order.Namespace).Get(item.Name)
+		if apierrors.IsNotFound(err) {
+			missing = append(missing, item.Name)
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+		pizzaCost, err := cost.Cost(pizza, c.toppingLister, c.promotionLister, c.clock.Now())
+		if err != nil {
+			return 0, fmt.Errorf("no cost for pizza %s: %w", item.Name, err)
+		}
+		total += pizzaCost * float64(item.Quantity)
+	}
+	if len(missing) > 0 {
+		return 0, fmt.Errorf("pizzas not found: %s", strings.Join(missing, ", "))
+	}
+	return total, nil
+}
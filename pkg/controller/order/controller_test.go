@@ -0,0 +1,191 @@
+package order
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1alpha1"
+	restaurantv1beta2 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+var (
+	created = metav1.NewTime(time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+	now     = metav1.NewTime(created.Add(time.Hour))
+)
+
+// newTestController returns a controller which computes totals from the
+// pizzas and toppings given, without promotions.
+func newTestController(t *testing.T, objs ...metav1.Object) *Controller {
+	pizzas := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	toppings := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, obj := range objs {
+		var err error
+		switch obj.(type) {
+		case *v1alpha1.Pizza:
+			err = pizzas.Add(obj)
+		case *v1alpha1.Topping:
+			err = toppings.Add(obj)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &Controller{
+		pizzaLister:     restaurantv1alpha1.NewPizzaLister(pizzas),
+		toppingLister:   restaurantv1alpha1.NewToppingLister(toppings),
+		promotionLister: restaurantv1beta2.NewPromotionLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		clock:           clocktesting.NewFakeClock(now.Time),
+	}
+}
+
+func TestNextStatus(t *testing.T) {
+	c := newTestController(t,
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "tomato"}, Spec: v1alpha1.ToppingSpec{Cost: 0.5}},
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "salami"}, Spec: v1alpha1.ToppingSpec{Cost: 1}},
+		&v1alpha1.Pizza{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "salami"},
+			Spec:       v1alpha1.PizzaSpec{Toppings: []string{"tomato", "salami"}},
+		},
+	)
+	order := func(phase v1beta1.OrderPhase, pizzas []v1beta1.OrderItem, status v1beta1.OrderStatus) *v1beta1.Order {
+		return &v1beta1.Order{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "lunch", Generation: 2},
+			Spec:       v1beta1.OrderSpec{Pizzas: pizzas, Phase: phase},
+			Status:     status,
+		}
+	}
+	salami := []v1beta1.OrderItem{{Name: "salami", Quantity: 2}}
+	missing := []v1beta1.OrderItem{{Name: "salami", Quantity: 2}, {Name: "hawaii", Quantity: 1}}
+	pending := v1beta1.OrderPhaseTime{Phase: v1beta1.OrderPending, Time: created}
+	accepted := v1beta1.OrderPhaseTime{Phase: v1beta1.OrderAccepted, Time: created}
+
+	tests := []struct {
+		name  string
+		order *v1beta1.Order
+		want  *v1beta1.OrderStatus
+	}{
+		{
+			name:  "new order",
+			order: order("", salami, v1beta1.OrderStatus{}),
+			want: &v1beta1.OrderStatus{
+				Phase:              v1beta1.OrderPending,
+				PhaseTimes:         []v1beta1.OrderPhaseTime{{Phase: v1beta1.OrderPending, Time: now}},
+				Total:              3,
+				ObservedGeneration: 2,
+			},
+		},
+		{
+			name:  "pending total follows the pizzas",
+			order: order(v1beta1.OrderPending, salami, v1beta1.OrderStatus{Phase: v1beta1.OrderPending, PhaseTimes: []v1beta1.OrderPhaseTime{pending}, Total: 1}),
+			want: &v1beta1.OrderStatus{
+				Phase:              v1beta1.OrderPending,
+				PhaseTimes:         []v1beta1.OrderPhaseTime{pending},
+				Total:              3,
+				ObservedGeneration: 2,
+			},
+		},
+		{
+			name:  "accept with the total computed on leaving pending",
+			order: order(v1beta1.OrderAccepted, salami, v1beta1.OrderStatus{Phase: v1beta1.OrderPending, PhaseTimes: []v1beta1.OrderPhaseTime{pending}, Total: 1}),
+			want: &v1beta1.OrderStatus{
+				Phase:              v1beta1.OrderAccepted,
+				PhaseTimes:         []v1beta1.OrderPhaseTime{pending, {Phase: v1beta1.OrderAccepted, Time: now}},
+				Total:              3,
+				ObservedGeneration: 2,
+			},
+		},
+		{
+			name:  "total frozen after pending",
+			order: order(v1beta1.OrderBaking, salami, v1beta1.OrderStatus{Phase: v1beta1.OrderAccepted, PhaseTimes: []v1beta1.OrderPhaseTime{pending, accepted}, Total: 1}),
+			want: &v1beta1.OrderStatus{
+				Phase:              v1beta1.OrderBaking,
+				PhaseTimes:         []v1beta1.OrderPhaseTime{pending, accepted, {Phase: v1beta1.OrderBaking, Time: now}},
+				Total:              1,
+				ObservedGeneration: 2,
+			},
+		},
+		{
+			name:  "total frozen with missing pizzas after pending",
+			order: order(v1beta1.OrderAccepted, missing, v1beta1.OrderStatus{Phase: v1beta1.OrderAccepted, PhaseTimes: []v1beta1.OrderPhaseTime{pending, accepted}, Total: 1}),
+			want: &v1beta1.OrderStatus{
+				Phase:              v1beta1.OrderAccepted,
+				PhaseTimes:         []v1beta1.OrderPhaseTime{pending, accepted},
+				Total:              1,
+				ObservedGeneration: 2,
+			},
+		},
+		{
+			name:  "one phase at a time",
+			order: order(v1beta1.OrderBaking, salami, v1beta1.OrderStatus{Phase: v1beta1.OrderPending, PhaseTimes: []v1beta1.OrderPhaseTime{pending}}),
+			want: &v1beta1.OrderStatus{
+				Phase:              v1beta1.OrderPending,
+				PhaseTimes:         []v1beta1.OrderPhaseTime{pending},
+				Total:              3,
+				Message:            "cannot move from Pending to Baking",
+				ObservedGeneration: 2,
+			},
+		},
+		{
+			name:  "no way back",
+			order: order(v1beta1.OrderPending, salami, v1beta1.OrderStatus{Phase: v1beta1.OrderAccepted, PhaseTimes: []v1beta1.OrderPhaseTime{pending, accepted}, Total: 1}),
+			want: &v1beta1.OrderStatus{
+				Phase:              v1beta1.OrderAccepted,
+				PhaseTimes:         []v1beta1.OrderPhaseTime{pending, accepted},
+				Total:              1,
+				Message:            "cannot move from Accepted to Pending",
+				ObservedGeneration: 2,
+			},
+		},
+		{
+			name: "terminal",
+			order: order(v1beta1.OrderCancelled, salami, v1beta1.OrderStatus{
+				Phase:      v1beta1.OrderDelivered,
+				PhaseTimes: []v1beta1.OrderPhaseTime{pending, {Phase: v1beta1.OrderDelivered, Time: created}},
+				Total:      1,
+			}),
+			want: &v1beta1.OrderStatus{
+				Phase:              v1beta1.OrderDelivered,
+				PhaseTimes:         []v1beta1.OrderPhaseTime{pending, {Phase: v1beta1.OrderDelivered, Time: created}},
+				Total:              1,
+				Message:            "cannot move from Delivered to Cancelled",
+				ObservedGeneration: 2,
+			},
+		},
+		{
+			name:  "missing pizzas keep the order pending",
+			order: order(v1beta1.OrderAccepted, missing, v1beta1.OrderStatus{Phase: v1beta1.OrderPending, PhaseTimes: []v1beta1.OrderPhaseTime{pending}, Total: 1}),
+			want: &v1beta1.OrderStatus{
+				Phase:              v1beta1.OrderPending,
+				PhaseTimes:         []v1beta1.OrderPhaseTime{pending},
+				Total:              1,
+				Message:            "pizzas not found: hawaii",
+				ObservedGeneration: 2,
+			},
+		},
+		{
+			name:  "cancelled while pending with missing pizzas",
+			order: order(v1beta1.OrderCancelled, missing, v1beta1.OrderStatus{Phase: v1beta1.OrderPending, PhaseTimes: []v1beta1.OrderPhaseTime{pending}, Total: 1}),
+			want: &v1beta1.OrderStatus{
+				Phase:              v1beta1.OrderCancelled,
+				PhaseTimes:         []v1beta1.OrderPhaseTime{pending, {Phase: v1beta1.OrderCancelled, Time: now}},
+				Total:              1,
+				Message:            "pizzas not found: hawaii",
+				ObservedGeneration: 2,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := c.nextStatus(test.order)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("unexpected status (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+// Package recipe implements the controller which resolves the PizzaRecipes
+// of Pizzas into their status and propagates recipe changes to them.
+package recipe
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	applyv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/applyconfiguration/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	restaurantinformers "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1alpha1"
+	restaurantv1beta2 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1beta2"
+	"github.com/zeroisme/pizza-crd/pkg/webhook/conversion"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// ControllerName is the name of the controller in logs and the default field
+// manager of the status it applies.
+const ControllerName = "pizza-recipe-controller"
+
+// ConditionRecipeResolved is the type of the Pizza condition which tells
+// whether the recipe of the pizza exists and its toppings are on the pizza.
+const ConditionRecipeResolved = "RecipeResolved"
+
+// recipeIndex indexes Pizzas by the namespaced name of their recipe.
+const recipeIndex = "recipe"
+
+// Controller applies status.recipe and the RecipeResolved condition of Pizzas
+// with a recipe. For recipes with the Resolve mode, status.recipe holds the
+// toppings of the recipe. For recipes with the Expand mode, the mutating
+// webhook writes them into spec.toppings, and the controller updates
+// spec.toppings when the recipe changes if the recipe propagates changes.
+type Controller struct {
+	clientset    versioned.Interface
+	pizzaLister  restaurantv1alpha1.PizzaLister
+	pizzaIndexer cache.Indexer
+	recipeLister restaurantv1beta2.PizzaRecipeLister
+	synced       []cache.InformerSynced
+	queue        workqueue.RateLimitingInterface
+	fieldManager string
+}
+
+// NewController creates a recipe controller. The informers have to be
+// started after the controller was created.
+func NewController(clientset versioned.Interface, informers restaurantinformers.SharedInformerFactory, fieldManager string) (*Controller, error) {
+	pizzaInformer := informers.Restaurant().V1alpha1().Pizzas()
+	recipeInformer := informers.Restaurant().V1beta2().PizzaRecipes()
+	if err := pizzaInformer.Informer().AddIndexers(cache.Indexers{recipeIndex: indexByRecipe}); err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		clientset:    clientset,
+		pizzaLister:  pizzaInformer.Lister(),
+		pizzaIndexer: pizzaInformer.Informer().GetIndexer(),
+		recipeLister: recipeInformer.Lister(),
+		synced:       []cache.InformerSynced{pizzaInformer.Informer().HasSynced, recipeInformer.Informer().HasSynced},
+		queue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+		fieldManager: fieldManager,
+	}
+
+	if _, err := pizzaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueuePizza,
+		UpdateFunc: func(_, obj interface{}) { c.enqueuePizza(obj) },
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := recipeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueRecipePizzas,
+		UpdateFunc: func(old, obj interface{}) {
+			if old.(*v1beta2.PizzaRecipe).Generation != obj.(*v1beta2.PizzaRecipe).Generation {
+				c.enqueueRecipePizzas(obj)
+			}
+		},
+		DeleteFunc: c.enqueueRecipePizzas,
+	}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func indexByRecipe(obj interface{}) ([]string, error) {
+	pizza, ok := obj.(*v1alpha1.Pizza)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object %T", obj)
+	}
+	if pizza.Spec.RecipeRef == nil {
+		return nil, nil
+	}
+	return []string{pizza.Namespace + "/" + pizza.Spec.RecipeRef.Name}, nil
+}
+
+func (c *Controller) enqueuePizza(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// enqueueRecipePizzas enqueues the Pizzas with a changed recipe.
+func (c *Controller) enqueueRecipePizzas(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	pizzas, err := c.pizzaIndexer.ByIndex(recipeIndex, key)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, pizza := range pizzas {
+		c.enqueuePizza(pizza)
+	}
+}
+
+// Run processes Pizzas with the given number of workers until ctx is done.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithName(ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller", "workers", workers)
+	defer logger.Info("Shutting down controller")
+
+	if !cache.WaitForNamedCacheSync(ControllerName, ctx.Done(), c.synced...) {
+		return
+	}
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(ctx, key.(string)); err != nil {
+		utilruntime.HandleError(fmt.Errorf("syncing pizza %q failed: %w", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) sync(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	pizza, err := c.pizzaLister.Pizzas(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	logger := klog.FromContext(ctx).WithValues("pizza", klog.KObj(pizza))
+	ctx = klog.NewContext(ctx, logger)
+
+	existing := meta.FindStatusCondition(pizza.Status.Conditions, ConditionRecipeResolved)
+	if pizza.Spec.RecipeRef == nil {
+		if pizza.Status.Recipe == nil && existing == nil {
+			return nil
+		}
+		// an apply without fields removes the fields of the controller
+		logger.V(2).Info("Removing recipe status")
+		return c.apply(ctx, pizza, applyv1alpha1.PizzaStatus())
+	}
+
+	recipeName := pizza.Spec.RecipeRef.Name
+	condition := metav1.Condition{
+		Type:               ConditionRecipeResolved,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: pizza.Generation,
+	}
+	recipeStatus := pizza.Status.Recipe
+	recipe, err := c.recipeLister.PizzaRecipes(namespace).Get(recipeName)
+	if apierrors.IsNotFound(err) {
+		// the pizza keeps the toppings it got, and is retried when the
+		// recipe is created
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "RecipeNotFound"
+		condition.Message = fmt.Sprintf("pizza recipe %q not found", recipeName)
+	} else if err != nil {
+		return err
+	} else {
+		pizza, recipeStatus, err = c.resolve(ctx, pizza, recipe)
+		if err != nil {
+			return err
+		}
+		condition.ObservedGeneration = pizza.Generation
+		if recipe.EffectiveMode() == v1beta2.RecipeResolve {
+			condition.Reason = "Resolved"
+			condition.Message = fmt.Sprintf("the toppings of recipe %q are in status.recipe", recipeName)
+		} else {
+			condition.Reason = "Expanded"
+			condition.Message = fmt.Sprintf("the toppings of recipe %q are in spec.toppings", recipeName)
+		}
+	}
+
+	if apiequality.Semantic.DeepEqual(pizza.Status.Recipe, recipeStatus) &&
+		existing != nil && existing.Status == condition.Status && existing.Message == condition.Message &&
+		existing.ObservedGeneration == condition.ObservedGeneration {
+		return nil
+	}
+	condition.LastTransitionTime = metav1.Now()
+	if existing != nil && existing.Status == condition.Status {
+		condition.LastTransitionTime = existing.LastTransitionTime
+	}
+
+	// every apply contains all fields of the controller, because fields left
+	// out would be removed
+	logger.V(2).Info("Applying recipe status", "recipe", recipeName, "resolved", condition.Status)
+	statusApply := applyv1alpha1.PizzaStatus().WithConditions(condition)
+	if recipeStatus != nil {
+		statusApply.WithRecipe(applyv1alpha1.RecipeStatus().
+			WithToppings(recipeStatus.Toppings...).
+			WithRecipeGeneration(recipeStatus.RecipeGeneration).
+			WithObservedGeneration(recipeStatus.ObservedGeneration))
+	}
+	return c.apply(ctx, pizza, statusApply)
+}
+
+// resolve returns the recipe status of a pizza with an existing recipe. The
+// toppings are resolved again when the pizza changed, or when the recipe
+// changed and propagates its changes. For the Expand mode, spec.toppings is
+// updated instead, and the updated pizza is returned.
+func (c *Controller) resolve(ctx context.Context, pizza *v1alpha1.Pizza, recipe *v1beta2.PizzaRecipe) (*v1alpha1.Pizza, *v1alpha1.RecipeStatus, error) {
+	current := pizza.Status.Recipe
+	changed := current == nil || current.ObservedGeneration != pizza.Generation
+	outdated := current != nil && recipe.Spec.Propagate && current.RecipeGeneration != recipe.Generation
+	if !changed && !outdated {
+		return pizza, current, nil
+	}
+
+	hubObj, err := conversion.Convert(pizza, v1beta2.SchemeGroupVersion.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	hub := hubObj.(*v1beta2.Pizza)
+	toppings := recipe.Expand(hub.Spec.Additions, hub.Spec.Removals)
+
+	if recipe.EffectiveMode() == v1beta2.RecipeResolve {
+		var names []string
+		for _, t := range toppings {
+			for i := 0; i < t.Quantity; i++ {
+				names = append(names, t.Name)
+			}
+		}
+		return pizza, &v1alpha1.RecipeStatus{
+			Toppings:           names,
+			RecipeGeneration:   recipe.Generation,
+			ObservedGeneration: pizza.Generation,
+		}, nil
+	}
+
+	// In the Expand mode the webhook expanded the recipe when the pizza was
+	// created or its recipe fields changed. The recipe generation is only
+	// advanced once spec.toppings is known to match it.
+	status := &v1alpha1.RecipeStatus{RecipeGeneration: recipe.Generation, ObservedGeneration: pizza.Generation}
+	if current != nil && !outdated {
+		status.RecipeGeneration = current.RecipeGeneration
+	}
+	if outdated && !apiequality.Semantic.DeepEqual(hub.Spec.Toppings, toppings) {
+		hub.Spec.Toppings = toppings
+		out, err := conversion.Convert(hub, v1alpha1.SchemeGroupVersion.String())
+		if err != nil {
+			return nil, nil, err
+		}
+		updated := pizza.DeepCopy()
+		updated.Spec.Toppings = out.(*v1alpha1.Pizza).Spec.Toppings
+		klog.FromContext(ctx).V(2).Info("Propagating recipe", "recipe", klog.KObj(recipe), "generation", recipe.Generation)
+		if updated, err = c.clientset.RestaurantV1alpha1().Pizzas(pizza.Namespace).Update(ctx, updated, metav1.UpdateOptions{FieldManager: c.fieldManager}); err != nil {
+			return nil, nil, err
+		}
+		pizza = updated
+		status.ObservedGeneration = pizza.Generation
+	}
+	return pizza, status, nil
+}
+
+func (c *Controller) apply(ctx context.Context, pizza *v1alpha1.Pizza, statusApply *applyv1alpha1.PizzaStatusApplyConfiguration) error {
+	status := applyv1alpha1.Pizza(pizza.Name, pizza.Namespace).WithStatus(statusApply)
+	_, err := c.clientset.RestaurantV1alpha1().Pizzas(pizza.Namespace).ApplyStatus(ctx, status, metav1.ApplyOptions{FieldManager: c.fieldManager, Force: true})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,332 @@
+// Package stock implements the controller which reserves the stock of
+// Toppings for the Pizzas using them.
+package stock
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/controller/index"
+	applyv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/applyconfiguration/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned"
+	restaurantinformers "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// ControllerName is the name of the controller in logs and the default field
+// manager of the status it applies.
+const ControllerName = "pizza-stock-controller"
+
+// ConditionStockReserved is the type of the Pizza condition which tells
+// whether the stock of all toppings is reserved for the pizza.
+const ConditionStockReserved = "StockReserved"
+
+// reservationIndex indexes Toppings by the keys of the Pizzas they have
+// reservations for.
+const reservationIndex = "reservation"
+
+// Controller reserves units of the stock of Toppings for each Pizza, and
+// releases them when the pizza no longer needs them or is deleted. Toppings
+// are updated with their resourceVersion, so concurrent reservations, e.g.
+// from several workers, never reserve more than the stock.
+type Controller struct {
+	clientset      versioned.Interface
+	pizzaLister    restaurantv1alpha1.PizzaLister
+	pizzaIndexer   cache.Indexer
+	toppingLister  restaurantv1alpha1.ToppingLister
+	toppingIndexer cache.Indexer
+	synced         []cache.InformerSynced
+	queue          workqueue.RateLimitingInterface
+	fieldManager   string
+}
+
+// NewController creates a stock controller. The informers have to be started
+// after the controller was created.
+func NewController(clientset versioned.Interface, informers restaurantinformers.SharedInformerFactory, fieldManager string) (*Controller, error) {
+	pizzaInformer := informers.Restaurant().V1alpha1().Pizzas()
+	toppingInformer := informers.Restaurant().V1alpha1().Toppings()
+	if err := index.AddPizzaTopping(pizzaInformer.Informer()); err != nil {
+		return nil, err
+	}
+	if err := toppingInformer.Informer().AddIndexers(cache.Indexers{reservationIndex: indexByReservation}); err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		clientset:      clientset,
+		pizzaLister:    pizzaInformer.Lister(),
+		pizzaIndexer:   pizzaInformer.Informer().GetIndexer(),
+		toppingLister:  toppingInformer.Lister(),
+		toppingIndexer: toppingInformer.Informer().GetIndexer(),
+		synced:         []cache.InformerSynced{pizzaInformer.Informer().HasSynced, toppingInformer.Informer().HasSynced},
+		queue:          workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+		fieldManager:   fieldManager,
+	}
+
+	if _, err := pizzaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueuePizza,
+		UpdateFunc: func(_, obj interface{}) { c.enqueuePizza(obj) },
+		DeleteFunc: c.enqueuePizza,
+	}); err != nil {
+		return nil, err
+	}
+	// pizzas waiting for stock are retried when the stock changes or other
+	// pizzas release their reservations
+	if _, err := toppingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueToppingPizzas,
+		UpdateFunc: func(old, obj interface{}) {
+			oldAvailable, oldLimited := old.(*v1alpha1.Topping).Available()
+			available, limited := obj.(*v1alpha1.Topping).Available()
+			if oldLimited != limited || available > oldAvailable {
+				c.enqueueToppingPizzas(obj)
+			}
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func indexByReservation(obj interface{}) ([]string, error) {
+	topping, ok := obj.(*v1alpha1.Topping)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object %T", obj)
+	}
+	keys := make([]string, 0, len(topping.Status.Reservations))
+	for key := range topping.Status.Reservations {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (c *Controller) enqueuePizza(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// enqueueToppingPizzas enqueues the Pizzas with a changed topping.
+func (c *Controller) enqueueToppingPizzas(obj interface{}) {
+	topping, ok := obj.(*v1alpha1.Topping)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("unexpected object %T", obj))
+		return
+	}
+	pizzas, err := c.pizzaIndexer.ByIndex(index.PizzaTopping, topping.Name)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, pizza := range pizzas {
+		c.enqueuePizza(pizza)
+	}
+}
+
+// Run processes Pizzas with the given number of workers until ctx is done.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithName(ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller", "workers", workers)
+	defer logger.Info("Shutting down controller")
+
+	if !cache.WaitForNamedCacheSync(ControllerName, ctx.Done(), c.synced...) {
+		return
+	}
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(ctx, key.(string)); err != nil {
+		utilruntime.HandleError(fmt.Errorf("syncing pizza %q failed: %w", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) sync(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	pizza, err := c.pizzaLister.Pizzas(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		pizza = nil
+	} else if err != nil {
+		return err
+	}
+	logger := klog.FromContext(ctx).WithValues("pizza", key)
+	ctx = klog.NewContext(ctx, logger)
+
+	// the units wanted per topping, 0 to release a reservation
+	wanted := map[string]int64{}
+	reserved, err := c.toppingIndexer.ByIndex(reservationIndex, key)
+	if err != nil {
+		return err
+	}
+	for _, obj := range reserved {
+		wanted[obj.(*v1alpha1.Topping).Name] = 0
+	}
+	if pizza != nil {
+		for topping, units := range needed(pizza.AllToppings()) {
+			wanted[topping] = units
+		}
+	}
+
+	toppings := make([]string, 0, len(wanted))
+	for topping := range wanted {
+		toppings = append(toppings, topping)
+	}
+	sort.Strings(toppings)
+	var missing, short []string
+	for _, topping := range toppings {
+		ok, err := c.reserve(ctx, topping, key, wanted[topping])
+		if apierrors.IsNotFound(err) {
+			if wanted[topping] > 0 {
+				missing = append(missing, topping)
+			}
+		} else if err != nil {
+			return err
+		} else if !ok {
+			short = append(short, topping)
+		}
+	}
+	if pizza == nil {
+		return nil
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionStockReserved,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Reserved",
+		Message:            "the stock of all toppings is reserved",
+		ObservedGeneration: pizza.Generation,
+	}
+	switch {
+	case len(missing) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ToppingNotFound"
+		condition.Message = fmt.Sprintf("toppings not found: %s", strings.Join(missing, ", "))
+	case len(short) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "OutOfStock"
+		condition.Message = fmt.Sprintf("not enough stock of %s", strings.Join(short, ", "))
+	}
+	existing := meta.FindStatusCondition(pizza.Status.Conditions, ConditionStockReserved)
+	if existing != nil && existing.Status == condition.Status && existing.Reason == condition.Reason &&
+		existing.Message == condition.Message && existing.ObservedGeneration == condition.ObservedGeneration {
+		return nil
+	}
+	condition.LastTransitionTime = metav1.Now()
+	if existing != nil && existing.Status == condition.Status {
+		condition.LastTransitionTime = existing.LastTransitionTime
+	}
+
+	logger.V(2).Info("Applying condition", "status", condition.Status, "reason", condition.Reason)
+	status := applyv1alpha1.Pizza(name, namespace).
+		WithStatus(applyv1alpha1.PizzaStatus().WithConditions(condition))
+	_, err = c.clientset.RestaurantV1alpha1().Pizzas(namespace).ApplyStatus(ctx, status, metav1.ApplyOptions{FieldManager: c.fieldManager, Force: true})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// reserve sets the reservation of a pizza on a topping to units and returns
+// false if the stock is too low. An existing reservation is kept in that
+// case. Toppings without a stock limit hold no reservations.
+func (c *Controller) reserve(ctx context.Context, toppingName, pizzaKey string, units int64) (bool, error) {
+	ok := true
+	topping, err := c.toppingLister.Get(toppingName)
+	if err != nil {
+		return false, err
+	}
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		available, limited := topping.Available()
+		if !limited {
+			units = 0
+		}
+		current := topping.Status.Reservations[pizzaKey]
+		if current == units {
+			return nil
+		}
+		if ok = units < current || units-current <= available; !ok {
+			return nil
+		}
+
+		topping = topping.DeepCopy()
+		setReservation(topping, pizzaKey, units)
+		klog.FromContext(ctx).V(2).Info("Reserving stock", "topping", toppingName, "old", current, "new", units)
+		_, err := c.clientset.RestaurantV1alpha1().Toppings().UpdateStatus(ctx, topping, metav1.UpdateOptions{FieldManager: c.fieldManager})
+		if apierrors.IsConflict(err) {
+			// the informer may lag behind, so fetch the latest version
+			var getErr error
+			if topping, getErr = c.clientset.RestaurantV1alpha1().Toppings().Get(ctx, toppingName, metav1.GetOptions{}); getErr != nil {
+				return getErr
+			}
+		}
+		return err
+	})
+	return ok, err
+}
+
+// setReservation sets the units reserved for a pizza on a topping, removing
+// the reservation for zero units, and updates the total.
+func setReservation(topping *v1alpha1.Topping, pizzaKey string, units int64) {
+	if units == 0 {
+		delete(topping.Status.Reservations, pizzaKey)
+	} else {
+		if topping.Status.Reservations == nil {
+			topping.Status.Reservations = map[string]int64{}
+		}
+		topping.Status.Reservations[pizzaKey] = units
+	}
+	topping.Status.Reserved = 0
+	for _, n := range topping.Status.Reservations {
+		topping.Status.Reserved += n
+	}
+}
+
+// needed returns the units needed per topping for the toppings of a v1alpha1
+// Pizza, where every piece is a list entry.
+func needed(toppings []string) map[string]int64 {
+	needed := map[string]int64{}
+	for _, name := range toppings {
+		needed[name]++
+	}
+	return needed
+}
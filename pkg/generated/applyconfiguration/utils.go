@@ -0,0 +1,127 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package applyconfiguration
+
+import (
+	v1alpha1 "github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	v1beta1 "github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	v1beta2 "github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/applyconfiguration/restaurant/v1alpha1"
+	restaurantv1beta1 "github.com/zeroisme/pizza-crd/pkg/generated/applyconfiguration/restaurant/v1beta1"
+	restaurantv1beta2 "github.com/zeroisme/pizza-crd/pkg/generated/applyconfiguration/restaurant/v1beta2"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ForKind returns an apply configuration type for the given GroupVersionKind, or nil if no
+// apply configuration type exists for the given GroupVersionKind.
+func ForKind(kind schema.GroupVersionKind) interface{} {
+	switch kind {
+	// Group=restaurant.programming-kubernetes.info, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithKind("AppliedDiscount"):
+		return &restaurantv1alpha1.AppliedDiscountApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("CostBreakdown"):
+		return &restaurantv1alpha1.CostBreakdownApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("Nutrition"):
+		return &restaurantv1alpha1.NutritionApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("Pizza"):
+		return &restaurantv1alpha1.PizzaApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("PizzaRecipeReference"):
+		return &restaurantv1alpha1.PizzaRecipeReferenceApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("PizzaSpec"):
+		return &restaurantv1alpha1.PizzaSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("PizzaStatus"):
+		return &restaurantv1alpha1.PizzaStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("RecipeStatus"):
+		return &restaurantv1alpha1.RecipeStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("Topping"):
+		return &restaurantv1alpha1.ToppingApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ToppingSize"):
+		return &restaurantv1alpha1.ToppingSizeApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ToppingSpec"):
+		return &restaurantv1alpha1.ToppingSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ToppingStatus"):
+		return &restaurantv1alpha1.ToppingStatusApplyConfiguration{}
+
+		// Group=restaurant.programming-kubernetes.info, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithKind("AppliedDiscount"):
+		return &restaurantv1beta1.AppliedDiscountApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CostBreakdown"):
+		return &restaurantv1beta1.CostBreakdownApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("Nutrition"):
+		return &restaurantv1beta1.NutritionApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("Order"):
+		return &restaurantv1beta1.OrderApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("OrderItem"):
+		return &restaurantv1beta1.OrderItemApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("OrderPhaseTime"):
+		return &restaurantv1beta1.OrderPhaseTimeApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("OrderSpec"):
+		return &restaurantv1beta1.OrderSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("OrderStatus"):
+		return &restaurantv1beta1.OrderStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("Pizza"):
+		return &restaurantv1beta1.PizzaApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("PizzaRecipeReference"):
+		return &restaurantv1beta1.PizzaRecipeReferenceApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("PizzaSpec"):
+		return &restaurantv1beta1.PizzaSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("PizzaStatus"):
+		return &restaurantv1beta1.PizzaStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("PizzaTopping"):
+		return &restaurantv1beta1.PizzaToppingApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RecipeStatus"):
+		return &restaurantv1beta1.RecipeStatusApplyConfiguration{}
+
+		// Group=restaurant.programming-kubernetes.info, Version=v1beta2
+	case v1beta2.SchemeGroupVersion.WithKind("AppliedDiscount"):
+		return &restaurantv1beta2.AppliedDiscountApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("CostBreakdown"):
+		return &restaurantv1beta2.CostBreakdownApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("Nutrition"):
+		return &restaurantv1beta2.NutritionApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("Pizza"):
+		return &restaurantv1beta2.PizzaApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("PizzaRecipe"):
+		return &restaurantv1beta2.PizzaRecipeApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("PizzaRecipeReference"):
+		return &restaurantv1beta2.PizzaRecipeReferenceApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("PizzaRecipeSpec"):
+		return &restaurantv1beta2.PizzaRecipeSpecApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("PizzaSection"):
+		return &restaurantv1beta2.PizzaSectionApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("PizzaSpec"):
+		return &restaurantv1beta2.PizzaSpecApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("PizzaStatus"):
+		return &restaurantv1beta2.PizzaStatusApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("PizzaTopping"):
+		return &restaurantv1beta2.PizzaToppingApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("Promotion"):
+		return &restaurantv1beta2.PromotionApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("PromotionDiscount"):
+		return &restaurantv1beta2.PromotionDiscountApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("PromotionSelector"):
+		return &restaurantv1beta2.PromotionSelectorApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("PromotionSpec"):
+		return &restaurantv1beta2.PromotionSpecApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("RecipeStatus"):
+		return &restaurantv1beta2.RecipeStatusApplyConfiguration{}
+
+	}
+	return nil
+}
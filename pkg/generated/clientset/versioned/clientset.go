@@ -24,6 +24,7 @@ import (
 
 	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned/typed/restaurant/v1alpha1"
 	restaurantv1beta1 "github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned/typed/restaurant/v1beta1"
+	restaurantv1beta2 "github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned/typed/restaurant/v1beta2"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -33,6 +34,7 @@ type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	RestaurantV1alpha1() restaurantv1alpha1.RestaurantV1alpha1Interface
 	RestaurantV1beta1() restaurantv1beta1.RestaurantV1beta1Interface
+	RestaurantV1beta2() restaurantv1beta2.RestaurantV1beta2Interface
 }
 
 // Clientset contains the clients for groups.
@@ -40,6 +42,7 @@ type Clientset struct {
 	*discovery.DiscoveryClient
 	restaurantV1alpha1 *restaurantv1alpha1.RestaurantV1alpha1Client
 	restaurantV1beta1  *restaurantv1beta1.RestaurantV1beta1Client
+	restaurantV1beta2  *restaurantv1beta2.RestaurantV1beta2Client
 }
 
 // RestaurantV1alpha1 retrieves the RestaurantV1alpha1Client
@@ -52,6 +55,11 @@ func (c *Clientset) RestaurantV1beta1() restaurantv1beta1.RestaurantV1beta1Inter
 	return c.restaurantV1beta1
 }
 
+// RestaurantV1beta2 retrieves the RestaurantV1beta2Client
+func (c *Clientset) RestaurantV1beta2() restaurantv1beta2.RestaurantV1beta2Interface {
+	return c.restaurantV1beta2
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -104,6 +112,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.restaurantV1beta2, err = restaurantv1beta2.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -127,6 +139,7 @@ func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.restaurantV1alpha1 = restaurantv1alpha1.New(c)
 	cs.restaurantV1beta1 = restaurantv1beta1.New(c)
+	cs.restaurantV1beta2 = restaurantv1beta2.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
@@ -24,6 +24,8 @@ import (
 	fakerestaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned/typed/restaurant/v1alpha1/fake"
 	restaurantv1beta1 "github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned/typed/restaurant/v1beta1"
 	fakerestaurantv1beta1 "github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned/typed/restaurant/v1beta1/fake"
+	restaurantv1beta2 "github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned/typed/restaurant/v1beta2"
+	fakerestaurantv1beta2 "github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned/typed/restaurant/v1beta2/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -90,3 +92,8 @@ func (c *Clientset) RestaurantV1alpha1() restaurantv1alpha1.RestaurantV1alpha1In
 func (c *Clientset) RestaurantV1beta1() restaurantv1beta1.RestaurantV1beta1Interface {
 	return &fakerestaurantv1beta1.FakeRestaurantV1beta1{Fake: &c.Fake}
 }
+
+// RestaurantV1beta2 retrieves the RestaurantV1beta2Client
+func (c *Clientset) RestaurantV1beta2() restaurantv1beta2.RestaurantV1beta2Interface {
+	return &fakerestaurantv1beta2.FakeRestaurantV1beta2{Fake: &c.Fake}
+}
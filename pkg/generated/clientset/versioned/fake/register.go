@@ -21,6 +21,7 @@ package fake
 import (
 	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
 	restaurantv1beta1 "github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	restaurantv1beta2 "github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -34,6 +35,7 @@ var codecs = serializer.NewCodecFactory(scheme)
 var localSchemeBuilder = runtime.SchemeBuilder{
 	restaurantv1alpha1.AddToScheme,
 	restaurantv1beta1.AddToScheme,
+	restaurantv1beta2.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
@@ -20,11 +20,13 @@ package fake
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 
 	v1alpha1 "github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/applyconfiguration/restaurant/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
-	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	types "k8s.io/apimachinery/pkg/types"
 	watch "k8s.io/apimachinery/pkg/watch"
 	testing "k8s.io/client-go/testing"
@@ -36,9 +38,9 @@ type FakePizzas struct {
 	ns   string
 }
 
-var pizzasResource = schema.GroupVersionResource{Group: "restaurant.programming-kubernetes.info", Version: "v1alpha1", Resource: "pizzas"}
+var pizzasResource = v1alpha1.SchemeGroupVersion.WithResource("pizzas")
 
-var pizzasKind = schema.GroupVersionKind{Group: "restaurant.programming-kubernetes.info", Version: "v1alpha1", Kind: "Pizza"}
+var pizzasKind = v1alpha1.SchemeGroupVersion.WithKind("Pizza")
 
 // Get takes name of the pizza, and returns the corresponding pizza object, and an error if there is any.
 func (c *FakePizzas) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.Pizza, err error) {
@@ -140,3 +142,48 @@ func (c *FakePizzas) Patch(ctx context.Context, name string, pt types.PatchType,
 	}
 	return obj.(*v1alpha1.Pizza), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied pizza.
+func (c *FakePizzas) Apply(ctx context.Context, pizza *restaurantv1alpha1.PizzaApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Pizza, err error) {
+	if pizza == nil {
+		return nil, fmt.Errorf("pizza provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(pizza)
+	if err != nil {
+		return nil, err
+	}
+	name := pizza.Name
+	if name == nil {
+		return nil, fmt.Errorf("pizza.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(pizzasResource, c.ns, *name, types.ApplyPatchType, data), &v1alpha1.Pizza{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Pizza), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakePizzas) ApplyStatus(ctx context.Context, pizza *restaurantv1alpha1.PizzaApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Pizza, err error) {
+	if pizza == nil {
+		return nil, fmt.Errorf("pizza provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(pizza)
+	if err != nil {
+		return nil, err
+	}
+	name := pizza.Name
+	if name == nil {
+		return nil, fmt.Errorf("pizza.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(pizzasResource, c.ns, *name, types.ApplyPatchType, data, "status"), &v1alpha1.Pizza{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Pizza), err
+}
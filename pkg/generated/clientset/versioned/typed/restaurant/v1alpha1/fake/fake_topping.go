@@ -20,11 +20,13 @@ package fake
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 
 	v1alpha1 "github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/applyconfiguration/restaurant/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
-	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	types "k8s.io/apimachinery/pkg/types"
 	watch "k8s.io/apimachinery/pkg/watch"
 	testing "k8s.io/client-go/testing"
@@ -35,9 +37,9 @@ type FakeToppings struct {
 	Fake *FakeRestaurantV1alpha1
 }
 
-var toppingsResource = schema.GroupVersionResource{Group: "restaurant.programming-kubernetes.info", Version: "v1alpha1", Resource: "toppings"}
+var toppingsResource = v1alpha1.SchemeGroupVersion.WithResource("toppings")
 
-var toppingsKind = schema.GroupVersionKind{Group: "restaurant.programming-kubernetes.info", Version: "v1alpha1", Kind: "Topping"}
+var toppingsKind = v1alpha1.SchemeGroupVersion.WithKind("Topping")
 
 // Get takes name of the topping, and returns the corresponding topping object, and an error if there is any.
 func (c *FakeToppings) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.Topping, err error) {
@@ -96,6 +98,17 @@ func (c *FakeToppings) Update(ctx context.Context, topping *v1alpha1.Topping, op
 	return obj.(*v1alpha1.Topping), err
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeToppings) UpdateStatus(ctx context.Context, topping *v1alpha1.Topping, opts v1.UpdateOptions) (*v1alpha1.Topping, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateSubresourceAction(toppingsResource, "status", topping), &v1alpha1.Topping{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Topping), err
+}
+
 // Delete takes name of the topping and deletes it. Returns an error if one occurs.
 func (c *FakeToppings) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
@@ -120,3 +133,46 @@ func (c *FakeToppings) Patch(ctx context.Context, name string, pt types.PatchTyp
 	}
 	return obj.(*v1alpha1.Topping), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied topping.
+func (c *FakeToppings) Apply(ctx context.Context, topping *restaurantv1alpha1.ToppingApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Topping, err error) {
+	if topping == nil {
+		return nil, fmt.Errorf("topping provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(topping)
+	if err != nil {
+		return nil, err
+	}
+	name := topping.Name
+	if name == nil {
+		return nil, fmt.Errorf("topping.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(toppingsResource, *name, types.ApplyPatchType, data), &v1alpha1.Topping{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Topping), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeToppings) ApplyStatus(ctx context.Context, topping *restaurantv1alpha1.ToppingApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Topping, err error) {
+	if topping == nil {
+		return nil, fmt.Errorf("topping provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(topping)
+	if err != nil {
+		return nil, err
+	}
+	name := topping.Name
+	if name == nil {
+		return nil, fmt.Errorf("topping.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(toppingsResource, *name, types.ApplyPatchType, data, "status"), &v1alpha1.Topping{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Topping), err
+}
@@ -20,9 +20,12 @@ package v1alpha1
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 	"time"
 
 	v1alpha1 "github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/applyconfiguration/restaurant/v1alpha1"
 	scheme "github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned/scheme"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
@@ -47,6 +50,8 @@ type PizzaInterface interface {
 	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.PizzaList, error)
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.Pizza, err error)
+	Apply(ctx context.Context, pizza *restaurantv1alpha1.PizzaApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Pizza, err error)
+	ApplyStatus(ctx context.Context, pizza *restaurantv1alpha1.PizzaApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Pizza, err error)
 	PizzaExpansion
 }
 
@@ -193,3 +198,59 @@ func (c *pizzas) Patch(ctx context.Context, name string, pt types.PatchType, dat
 		Into(result)
 	return
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied pizza.
+func (c *pizzas) Apply(ctx context.Context, pizza *restaurantv1alpha1.PizzaApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Pizza, err error) {
+	if pizza == nil {
+		return nil, fmt.Errorf("pizza provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(pizza)
+	if err != nil {
+		return nil, err
+	}
+	name := pizza.Name
+	if name == nil {
+		return nil, fmt.Errorf("pizza.Name must be provided to Apply")
+	}
+	result = &v1alpha1.Pizza{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("pizzas").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *pizzas) ApplyStatus(ctx context.Context, pizza *restaurantv1alpha1.PizzaApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Pizza, err error) {
+	if pizza == nil {
+		return nil, fmt.Errorf("pizza provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(pizza)
+	if err != nil {
+		return nil, err
+	}
+
+	name := pizza.Name
+	if name == nil {
+		return nil, fmt.Errorf("pizza.Name must be provided to Apply")
+	}
+
+	result = &v1alpha1.Pizza{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("pizzas").
+		Name(*name).
+		SubResource("status").
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -20,9 +20,12 @@ package v1alpha1
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 	"time"
 
 	v1alpha1 "github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/applyconfiguration/restaurant/v1alpha1"
 	scheme "github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned/scheme"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
@@ -40,12 +43,15 @@ type ToppingsGetter interface {
 type ToppingInterface interface {
 	Create(ctx context.Context, topping *v1alpha1.Topping, opts v1.CreateOptions) (*v1alpha1.Topping, error)
 	Update(ctx context.Context, topping *v1alpha1.Topping, opts v1.UpdateOptions) (*v1alpha1.Topping, error)
+	UpdateStatus(ctx context.Context, topping *v1alpha1.Topping, opts v1.UpdateOptions) (*v1alpha1.Topping, error)
 	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
 	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
 	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.Topping, error)
 	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ToppingList, error)
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.Topping, err error)
+	Apply(ctx context.Context, topping *restaurantv1alpha1.ToppingApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Topping, err error)
+	ApplyStatus(ctx context.Context, topping *restaurantv1alpha1.ToppingApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Topping, err error)
 	ToppingExpansion
 }
 
@@ -128,6 +134,21 @@ func (c *toppings) Update(ctx context.Context, topping *v1alpha1.Topping, opts v
 	return
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *toppings) UpdateStatus(ctx context.Context, topping *v1alpha1.Topping, opts v1.UpdateOptions) (result *v1alpha1.Topping, err error) {
+	result = &v1alpha1.Topping{}
+	err = c.client.Put().
+		Resource("toppings").
+		Name(topping.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(topping).
+		Do(ctx).
+		Into(result)
+	return
+}
+
 // Delete takes name of the topping and deletes it. Returns an error if one occurs.
 func (c *toppings) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	return c.client.Delete().
@@ -166,3 +187,57 @@ func (c *toppings) Patch(ctx context.Context, name string, pt types.PatchType, d
 		Into(result)
 	return
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied topping.
+func (c *toppings) Apply(ctx context.Context, topping *restaurantv1alpha1.ToppingApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Topping, err error) {
+	if topping == nil {
+		return nil, fmt.Errorf("topping provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(topping)
+	if err != nil {
+		return nil, err
+	}
+	name := topping.Name
+	if name == nil {
+		return nil, fmt.Errorf("topping.Name must be provided to Apply")
+	}
+	result = &v1alpha1.Topping{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Resource("toppings").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *toppings) ApplyStatus(ctx context.Context, topping *restaurantv1alpha1.ToppingApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Topping, err error) {
+	if topping == nil {
+		return nil, fmt.Errorf("topping provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(topping)
+	if err != nil {
+		return nil, err
+	}
+
+	name := topping.Name
+	if name == nil {
+		return nil, fmt.Errorf("topping.Name must be provided to Apply")
+	}
+
+	result = &v1alpha1.Topping{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Resource("toppings").
+		Name(*name).
+		SubResource("status").
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
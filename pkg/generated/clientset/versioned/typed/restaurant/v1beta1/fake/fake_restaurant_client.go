@@ -28,6 +28,10 @@ type FakeRestaurantV1beta1 struct {
 	*testing.Fake
 }
 
+func (c *FakeRestaurantV1beta1) Orders(namespace string) v1beta1.OrderInterface {
+	return &FakeOrders{c, namespace}
+}
+
 func (c *FakeRestaurantV1beta1) Pizzas(namespace string) v1beta1.PizzaInterface {
 	return &FakePizzas{c, namespace}
 }
@@ -28,6 +28,7 @@ import (
 
 type RestaurantV1beta1Interface interface {
 	RESTClient() rest.Interface
+	OrdersGetter
 	PizzasGetter
 }
 
@@ -36,6 +37,10 @@ type RestaurantV1beta1Client struct {
 	restClient rest.Interface
 }
 
+func (c *RestaurantV1beta1Client) Orders(namespace string) OrderInterface {
+	return newOrders(c, namespace)
+}
+
 func (c *RestaurantV1beta1Client) Pizzas(namespace string) PizzaInterface {
 	return newPizzas(c, namespace)
 }
@@ -0,0 +1,189 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta2 "github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	restaurantv1beta2 "github.com/zeroisme/pizza-crd/pkg/generated/applyconfiguration/restaurant/v1beta2"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakePizzas implements PizzaInterface
+type FakePizzas struct {
+	Fake *FakeRestaurantV1beta2
+	ns   string
+}
+
+var pizzasResource = v1beta2.SchemeGroupVersion.WithResource("pizzas")
+
+var pizzasKind = v1beta2.SchemeGroupVersion.WithKind("Pizza")
+
+// Get takes name of the pizza, and returns the corresponding pizza object, and an error if there is any.
+func (c *FakePizzas) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta2.Pizza, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(pizzasResource, c.ns, name), &v1beta2.Pizza{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta2.Pizza), err
+}
+
+// List takes label and field selectors, and returns the list of Pizzas that match those selectors.
+func (c *FakePizzas) List(ctx context.Context, opts v1.ListOptions) (result *v1beta2.PizzaList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(pizzasResource, pizzasKind, c.ns, opts), &v1beta2.PizzaList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1beta2.PizzaList{ListMeta: obj.(*v1beta2.PizzaList).ListMeta}
+	for _, item := range obj.(*v1beta2.PizzaList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested pizzas.
+func (c *FakePizzas) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(pizzasResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a pizza and creates it.  Returns the server's representation of the pizza, and an error, if there is any.
+func (c *FakePizzas) Create(ctx context.Context, pizza *v1beta2.Pizza, opts v1.CreateOptions) (result *v1beta2.Pizza, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(pizzasResource, c.ns, pizza), &v1beta2.Pizza{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta2.Pizza), err
+}
+
+// Update takes the representation of a pizza and updates it. Returns the server's representation of the pizza, and an error, if there is any.
+func (c *FakePizzas) Update(ctx context.Context, pizza *v1beta2.Pizza, opts v1.UpdateOptions) (result *v1beta2.Pizza, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(pizzasResource, c.ns, pizza), &v1beta2.Pizza{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta2.Pizza), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakePizzas) UpdateStatus(ctx context.Context, pizza *v1beta2.Pizza, opts v1.UpdateOptions) (*v1beta2.Pizza, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(pizzasResource, "status", c.ns, pizza), &v1beta2.Pizza{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta2.Pizza), err
+}
+
+// Delete takes name of the pizza and deletes it. Returns an error if one occurs.
+func (c *FakePizzas) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(pizzasResource, c.ns, name, opts), &v1beta2.Pizza{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakePizzas) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(pizzasResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1beta2.PizzaList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched pizza.
+func (c *FakePizzas) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta2.Pizza, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(pizzasResource, c.ns, name, pt, data, subresources...), &v1beta2.Pizza{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta2.Pizza), err
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied pizza.
+func (c *FakePizzas) Apply(ctx context.Context, pizza *restaurantv1beta2.PizzaApplyConfiguration, opts v1.ApplyOptions) (result *v1beta2.Pizza, err error) {
+	if pizza == nil {
+		return nil, fmt.Errorf("pizza provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(pizza)
+	if err != nil {
+		return nil, err
+	}
+	name := pizza.Name
+	if name == nil {
+		return nil, fmt.Errorf("pizza.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(pizzasResource, c.ns, *name, types.ApplyPatchType, data), &v1beta2.Pizza{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta2.Pizza), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakePizzas) ApplyStatus(ctx context.Context, pizza *restaurantv1beta2.PizzaApplyConfiguration, opts v1.ApplyOptions) (result *v1beta2.Pizza, err error) {
+	if pizza == nil {
+		return nil, fmt.Errorf("pizza provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(pizza)
+	if err != nil {
+		return nil, err
+	}
+	name := pizza.Name
+	if name == nil {
+		return nil, fmt.Errorf("pizza.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(pizzasResource, c.ns, *name, types.ApplyPatchType, data, "status"), &v1beta2.Pizza{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta2.Pizza), err
+}
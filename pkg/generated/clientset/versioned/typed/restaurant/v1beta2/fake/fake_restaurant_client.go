@@ -0,0 +1,48 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1beta2 "github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned/typed/restaurant/v1beta2"
+	rest "k8s.io/client-go/rest"
+	testing "k8s.io/client-go/testing"
+)
+
+type FakeRestaurantV1beta2 struct {
+	*testing.Fake
+}
+
+func (c *FakeRestaurantV1beta2) Pizzas(namespace string) v1beta2.PizzaInterface {
+	return &FakePizzas{c, namespace}
+}
+
+func (c *FakeRestaurantV1beta2) PizzaRecipes(namespace string) v1beta2.PizzaRecipeInterface {
+	return &FakePizzaRecipes{c, namespace}
+}
+
+func (c *FakeRestaurantV1beta2) Promotions() v1beta2.PromotionInterface {
+	return &FakePromotions{c}
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *FakeRestaurantV1beta2) RESTClient() rest.Interface {
+	var ret *rest.RESTClient
+	return ret
+}
@@ -23,6 +23,7 @@ import (
 
 	v1alpha1 "github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
 	v1beta1 "github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	v1beta2 "github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -60,9 +61,19 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Restaurant().V1alpha1().Toppings().Informer()}, nil
 
 		// Group=restaurant.programming-kubernetes.info, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithResource("orders"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Restaurant().V1beta1().Orders().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("pizzas"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Restaurant().V1beta1().Pizzas().Informer()}, nil
 
+		// Group=restaurant.programming-kubernetes.info, Version=v1beta2
+	case v1beta2.SchemeGroupVersion.WithResource("pizzas"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Restaurant().V1beta2().Pizzas().Informer()}, nil
+	case v1beta2.SchemeGroupVersion.WithResource("pizzarecipes"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Restaurant().V1beta2().PizzaRecipes().Informer()}, nil
+	case v1beta2.SchemeGroupVersion.WithResource("promotions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Restaurant().V1beta2().Promotions().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
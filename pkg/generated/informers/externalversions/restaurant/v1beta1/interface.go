@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// Orders returns a OrderInformer.
+	Orders() OrderInformer
 	// Pizzas returns a PizzaInformer.
 	Pizzas() PizzaInformer
 }
@@ -39,6 +41,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// Orders returns a OrderInformer.
+func (v *version) Orders() OrderInformer {
+	return &orderInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Pizzas returns a PizzaInformer.
 func (v *version) Pizzas() PizzaInformer {
 	return &pizzaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
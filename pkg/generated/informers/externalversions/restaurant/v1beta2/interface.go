@@ -0,0 +1,59 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1beta2
+
+import (
+	internalinterfaces "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions/internalinterfaces"
+)
+
+// Interface provides access to all the informers in this group version.
+type Interface interface {
+	// Pizzas returns a PizzaInformer.
+	Pizzas() PizzaInformer
+	// PizzaRecipes returns a PizzaRecipeInformer.
+	PizzaRecipes() PizzaRecipeInformer
+	// Promotions returns a PromotionInformer.
+	Promotions() PromotionInformer
+}
+
+type version struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// New returns a new Interface.
+func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakListOptions internalinterfaces.TweakListOptionsFunc) Interface {
+	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
+}
+
+// Pizzas returns a PizzaInformer.
+func (v *version) Pizzas() PizzaInformer {
+	return &pizzaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// PizzaRecipes returns a PizzaRecipeInformer.
+func (v *version) PizzaRecipes() PizzaRecipeInformer {
+	return &pizzaRecipeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// Promotions returns a PromotionInformer.
+func (v *version) Promotions() PromotionInformer {
+	return &promotionInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
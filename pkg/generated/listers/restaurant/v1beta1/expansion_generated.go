@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1beta1
 
+// OrderListerExpansion allows custom methods to be added to
+// OrderLister.
+type OrderListerExpansion interface{}
+
+// OrderNamespaceListerExpansion allows custom methods to be added to
+// OrderNamespaceLister.
+type OrderNamespaceListerExpansion interface{}
+
 // PizzaListerExpansion allows custom methods to be added to
 // PizzaLister.
 type PizzaListerExpansion interface{}
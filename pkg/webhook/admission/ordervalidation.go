@@ -0,0 +1,189 @@
+package admission
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+)
+
+func ServeOrderValidation(w http.ResponseWriter, req *http.Request) {
+	logger := klog.FromContext(req.Context())
+
+	body, err := webhook.ReadBody(req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request body: %v", err), webhook.ReadBodyErrorCode(err))
+		return
+	}
+
+	webhook.LogBody(logger, "Handling request", body)
+	obj, gvk, err := webhook.Decode(req.Context(), webhook.Codecs.UniversalDeserializer(), body)
+	if err != nil {
+		msg := fmt.Sprintf("failed to deserialize body of %d bytes: %v", len(body), err)
+		logger.Error(err, "Failed to deserialize request body", "size", len(body))
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	var responseObj runtime.Object
+	switch *gvk {
+	case admissionv1.SchemeGroupVersion.WithKind("AdmissionReview"):
+		review, ok := obj.(*admissionv1.AdmissionReview)
+		if !ok {
+			msg := fmt.Sprintf("Expected a v1.AdmissionReview but got: %T", obj)
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		if review.Request == nil {
+			msg := "unexpected nil request"
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		ctx := withRequestLogger(req.Context(), review.Request.UID, review.Request.Namespace, review.Request.Name, review.Request.Operation)
+		allowed, result := doValidateOrder(ctx, review.Request.Object.Raw, review.Request.OldObject.Raw, review.Request.SubResource)
+		review.Response = &admissionv1.AdmissionResponse{UID: review.Request.UID, Allowed: allowed, Result: result}
+		review.Request = &admissionv1.AdmissionRequest{}
+		responseObj = review
+	case admissionv1beta1.SchemeGroupVersion.WithKind("AdmissionReview"):
+		review, ok := obj.(*admissionv1beta1.AdmissionReview)
+		if !ok {
+			msg := fmt.Sprintf("Expected a v1beta1.AdmissionReview but got: %T", obj)
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		if review.Request == nil {
+			msg := "unexpected nil request"
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		ctx := withRequestLogger(req.Context(), review.Request.UID, review.Request.Namespace, review.Request.Name, review.Request.Operation)
+		allowed, result := doValidateOrder(ctx, review.Request.Object.Raw, review.Request.OldObject.Raw, review.Request.SubResource)
+		review.Response = &admissionv1beta1.AdmissionResponse{UID: review.Request.UID, Allowed: allowed, Result: result}
+		review.Request = &admissionv1beta1.AdmissionRequest{}
+		responseObj = review
+	default:
+		msg := fmt.Sprintf("unexpected GroupVersionKind: %v", gvk)
+		logger.Error(nil, "Rejecting malformed request", "reason", msg)
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	webhook.SendResponse(w, req, responseObj)
+}
+
+// doValidateOrder validates the raw order of a review against the raw old
+// order, which is empty on create.
+func doValidateOrder(ctx context.Context, raw, oldRaw []byte, subResource string) (bool, *metav1.Status) {
+	order, err := decodeOrder(ctx, raw)
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to decode order")
+		return false, &metav1.Status{Message: err.Error(), Status: metav1.StatusFailure}
+	}
+	var oldOrder *v1beta1.Order
+	if len(oldRaw) > 0 {
+		if oldOrder, err = decodeOrder(ctx, oldRaw); err != nil {
+			klog.FromContext(ctx).Error(err, "Failed to decode old order")
+			return false, &metav1.Status{Message: err.Error(), Status: metav1.StatusFailure}
+		}
+	}
+
+	var errs field.ErrorList
+	switch {
+	case subResource == "status" && oldOrder != nil:
+		errs = ValidateOrderStatusUpdate(order, oldOrder)
+	case oldOrder != nil:
+		errs = ValidateOrderUpdate(order, oldOrder)
+	default:
+		errs = ValidateOrder(order)
+	}
+	if err := errs.ToAggregate(); err != nil {
+		klog.FromContext(ctx).V(2).Info("Rejecting invalid order", "err", err)
+		return false, &metav1.Status{Message: err.Error(), Status: metav1.StatusFailure}
+	}
+	return true, &metav1.Status{Message: "order is valid", Status: metav1.StatusSuccess}
+}
+
+func decodeOrder(ctx context.Context, raw []byte) (*v1beta1.Order, error) {
+	obj, _, err := webhook.Decode(ctx, webhook.Codecs.UniversalDeserializer(), raw)
+	if err != nil {
+		return nil, err
+	}
+	order, ok := obj.(*v1beta1.Order)
+	if !ok {
+		return nil, fmt.Errorf("unexpected order type: %T", obj)
+	}
+	return order, nil
+}
+
+// ValidateOrder checks a new Order. It has to start in Pending.
+func ValidateOrder(order *v1beta1.Order) field.ErrorList {
+	allErrs := validateOrderItems(field.NewPath("spec", "pizzas"), order.Spec.Pizzas)
+	if phase := order.Spec.Phase.Normalized(); phase != v1beta1.OrderPending {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "phase"), phase, "orders start in phase Pending"))
+	}
+	return allErrs
+}
+
+// ValidateOrderUpdate checks an update of an Order: the phase may only move
+// one phase forward or to Cancelled, and the pizzas are fixed once the order
+// is accepted.
+func ValidateOrderUpdate(order, oldOrder *v1beta1.Order) field.ErrorList {
+	allErrs := validateOrderItems(field.NewPath("spec", "pizzas"), order.Spec.Pizzas)
+	allErrs = append(allErrs, validatePhaseTransition(field.NewPath("spec", "phase"), oldOrder.Spec.Phase, order.Spec.Phase)...)
+	if oldOrder.Spec.Phase.Normalized() != v1beta1.OrderPending && !apiequality.Semantic.DeepEqual(order.Spec.Pizzas, oldOrder.Spec.Pizzas) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "pizzas"), fmt.Sprintf("the pizzas of an order in phase %s cannot be changed", oldOrder.Spec.Phase)))
+	}
+	return allErrs
+}
+
+// ValidateOrderStatusUpdate checks an update of the status of an Order, which
+// may not skip phases either.
+func ValidateOrderStatusUpdate(order, oldOrder *v1beta1.Order) field.ErrorList {
+	if len(oldOrder.Status.Phase) == 0 {
+		// the first status starts in any phase the spec allows
+		return nil
+	}
+	return validatePhaseTransition(field.NewPath("status", "phase"), oldOrder.Status.Phase, order.Status.Phase)
+}
+
+func validatePhaseTransition(fldPath *field.Path, old, phase v1beta1.OrderPhase) field.ErrorList {
+	if old.CanTransitionTo(phase) {
+		return nil
+	}
+	if old.Terminal() {
+		return field.ErrorList{field.Forbidden(fldPath, fmt.Sprintf("the order is %s already", old))}
+	}
+	return field.ErrorList{field.Invalid(fldPath, phase, fmt.Sprintf("cannot move from %s to %s", old.Normalized(), phase.Normalized()))}
+}
+
+func validateOrderItems(fldPath *field.Path, items []v1beta1.OrderItem) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(items) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "an order needs at least one pizza"))
+	}
+	names := sets.New[string]()
+	for i, item := range items {
+		if len(item.Name) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("name"), ""))
+		} else if names.Has(item.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), item.Name))
+		}
+		names.Insert(item.Name)
+		if item.Quantity < 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("quantity"), item.Quantity, "must be at least 1"))
+		}
+	}
+	return allErrs
+}
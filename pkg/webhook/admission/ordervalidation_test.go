@@ -0,0 +1,139 @@
+package admission_test
+
+import (
+	"testing"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/webhook/admission"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newOrder(spec, status v1beta1.OrderPhase, pizzas ...v1beta1.OrderItem) *v1beta1.Order {
+	if len(pizzas) == 0 {
+		pizzas = []v1beta1.OrderItem{{Name: "margherita", Quantity: 1}}
+	}
+	return &v1beta1.Order{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "lunch"},
+		Spec:       v1beta1.OrderSpec{Pizzas: pizzas, Phase: spec},
+		Status:     v1beta1.OrderStatus{Phase: status},
+	}
+}
+
+func TestValidateOrderUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new *v1beta1.Order
+		want     string
+	}{
+		{name: "unchanged", old: newOrder("", ""), new: newOrder("", "")},
+		{name: "empty to pending", old: newOrder("", ""), new: newOrder(v1beta1.OrderPending, "")},
+		{name: "empty to accepted", old: newOrder("", ""), new: newOrder(v1beta1.OrderAccepted, "")},
+		{name: "pending to accepted", old: newOrder(v1beta1.OrderPending, ""), new: newOrder(v1beta1.OrderAccepted, "")},
+		{name: "accepted to baking", old: newOrder(v1beta1.OrderAccepted, ""), new: newOrder(v1beta1.OrderBaking, "")},
+		{name: "baking to ready", old: newOrder(v1beta1.OrderBaking, ""), new: newOrder(v1beta1.OrderReady, "")},
+		{name: "ready to delivered", old: newOrder(v1beta1.OrderReady, ""), new: newOrder(v1beta1.OrderDelivered, "")},
+		{name: "pending to cancelled", old: newOrder(v1beta1.OrderPending, ""), new: newOrder(v1beta1.OrderCancelled, "")},
+		{name: "ready to cancelled", old: newOrder(v1beta1.OrderReady, ""), new: newOrder(v1beta1.OrderCancelled, "")},
+		{
+			name: "empty to baking",
+			old:  newOrder("", ""),
+			new:  newOrder(v1beta1.OrderBaking, ""),
+			want: `spec.phase: Invalid value: "Baking": cannot move from Pending to Baking`,
+		},
+		{
+			name: "accepted to pending",
+			old:  newOrder(v1beta1.OrderAccepted, ""),
+			new:  newOrder(v1beta1.OrderPending, ""),
+			want: `spec.phase: Invalid value: "Pending": cannot move from Accepted to Pending`,
+		},
+		{
+			name: "accepted to ready",
+			old:  newOrder(v1beta1.OrderAccepted, ""),
+			new:  newOrder(v1beta1.OrderReady, ""),
+			want: `spec.phase: Invalid value: "Ready": cannot move from Accepted to Ready`,
+		},
+		{
+			name: "delivered to cancelled",
+			old:  newOrder(v1beta1.OrderDelivered, ""),
+			new:  newOrder(v1beta1.OrderCancelled, ""),
+			want: "spec.phase: Forbidden: the order is Delivered already",
+		},
+		{
+			name: "cancelled to pending",
+			old:  newOrder(v1beta1.OrderCancelled, ""),
+			new:  newOrder(v1beta1.OrderPending, ""),
+			want: "spec.phase: Forbidden: the order is Cancelled already",
+		},
+		{
+			name: "pizzas changed while pending",
+			old:  newOrder(v1beta1.OrderPending, ""),
+			new:  newOrder(v1beta1.OrderPending, "", v1beta1.OrderItem{Name: "salami", Quantity: 2}),
+		},
+		{
+			name: "pizzas changed when accepting",
+			old:  newOrder(v1beta1.OrderPending, ""),
+			new:  newOrder(v1beta1.OrderAccepted, "", v1beta1.OrderItem{Name: "salami", Quantity: 2}),
+		},
+		{
+			name: "pizzas changed after pending",
+			old:  newOrder(v1beta1.OrderAccepted, ""),
+			new:  newOrder(v1beta1.OrderBaking, "", v1beta1.OrderItem{Name: "salami", Quantity: 2}),
+			want: "spec.pizzas: Forbidden: the pizzas of an order in phase Accepted cannot be changed",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expectOrderErrors(t, admission.ValidateOrderUpdate(test.new, test.old).ToAggregate(), test.want)
+		})
+	}
+}
+
+func TestValidateOrderStatusUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new *v1beta1.Order
+		want     string
+	}{
+		{name: "first status pending", old: newOrder("", ""), new: newOrder("", v1beta1.OrderPending)},
+		{name: "first status in any phase", old: newOrder("", ""), new: newOrder("", v1beta1.OrderBaking)},
+		{name: "unchanged", old: newOrder("", v1beta1.OrderBaking), new: newOrder("", v1beta1.OrderBaking)},
+		{name: "pending to accepted", old: newOrder("", v1beta1.OrderPending), new: newOrder("", v1beta1.OrderAccepted)},
+		{name: "baking to cancelled", old: newOrder("", v1beta1.OrderBaking), new: newOrder("", v1beta1.OrderCancelled)},
+		{name: "spec phase ignored", old: newOrder(v1beta1.OrderPending, v1beta1.OrderBaking), new: newOrder(v1beta1.OrderPending, v1beta1.OrderReady)},
+		{
+			name: "pending to ready",
+			old:  newOrder("", v1beta1.OrderPending),
+			new:  newOrder("", v1beta1.OrderReady),
+			want: `status.phase: Invalid value: "Ready": cannot move from Pending to Ready`,
+		},
+		{
+			name: "baking to accepted",
+			old:  newOrder("", v1beta1.OrderBaking),
+			new:  newOrder("", v1beta1.OrderAccepted),
+			want: `status.phase: Invalid value: "Accepted": cannot move from Baking to Accepted`,
+		},
+		{
+			name: "delivered to ready",
+			old:  newOrder("", v1beta1.OrderDelivered),
+			new:  newOrder("", v1beta1.OrderReady),
+			want: "status.phase: Forbidden: the order is Delivered already",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expectOrderErrors(t, admission.ValidateOrderStatusUpdate(test.new, test.old).ToAggregate(), test.want)
+		})
+	}
+}
+
+func expectOrderErrors(t *testing.T, err error, want string) {
+	t.Helper()
+	switch {
+	case len(want) == 0 && err != nil:
+		t.Errorf("unexpected error: %v", err)
+	case len(want) > 0 && err == nil:
+		t.Errorf("expected error %q, got none", want)
+	case len(want) > 0 && err.Error() != want:
+		t.Errorf("expected error %q, got %q", want, err.Error())
+	}
+}
@@ -1,35 +1,63 @@
 package admission
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 
 	"github.com/appscode/jsonpatch"
 	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
 	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	restaurantinformers "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions"
+	restaurantv1beta2 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1beta2"
 	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	"github.com/zeroisme/pizza-crd/pkg/webhook/conversion"
 	admissionv1 "k8s.io/api/admission/v1"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/tracing"
 	"k8s.io/klog/v2"
 )
 
-func ServePizzaAdmit(w http.ResponseWriter, req *http.Request) {
-	body, err := io.ReadAll(req.Body)
+func ServePizzaAdmit(informers restaurantinformers.SharedInformerFactory) func(http.ResponseWriter, *http.Request) {
+	recipeInformer := informers.Restaurant().V1beta2().PizzaRecipes().Informer()
+	recipeLister := informers.Restaurant().V1beta2().PizzaRecipes().Lister()
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		servePizzaAdmit(w, req, recipeInformer.HasSynced, recipeLister)
+	}
+}
+
+func servePizzaAdmit(w http.ResponseWriter, req *http.Request, recipesSynced cache.InformerSynced, recipeLister restaurantv1beta2.PizzaRecipeLister) {
+	body, err := webhook.ReadBody(req)
 	if err != nil {
-		http.Error(w, fmt.Errorf("failed to read body: %v", err).Error(), http.StatusBadRequest)
+		http.Error(w, fmt.Errorf("failed to read body: %v", err).Error(), webhook.ReadBodyErrorCode(err))
+		return
+	}
+	logger := klog.FromContext(req.Context())
+
+	// wait for the recipes until the request deadline
+	_, span := tracing.Start(req.Context(), "Wait for recipe informer")
+	synced := cache.WaitForCacheSync(req.Context().Done(), recipesSynced)
+	span.End(webhook.TraceThreshold)
+	if !synced {
+		http.Error(w, "recipe informer not synced yet", http.StatusInternalServerError)
+		return
 	}
 
 	// decode as admission review
-	klog.V(2).Infof("handling request: %v", body)
-	obj, gvk, err := webhook.Codecs.UniversalDeserializer().Decode(body, nil, nil)
+	webhook.LogBody(logger, "Handling request", body)
+	obj, gvk, err := webhook.Decode(req.Context(), webhook.Codecs.UniversalDeserializer(), body)
 	if err != nil {
-		msg := fmt.Sprintf("failed to deserialize body (%v) with error %v", string(body), err)
-		klog.Error(err)
+		msg := fmt.Sprintf("failed to deserialize body of %d bytes: %v", len(body), err)
+		logger.Error(err, "Failed to deserialize request body", "size", len(body))
 		http.Error(w, msg, http.StatusBadRequest)
 		return
 	}
@@ -41,61 +69,78 @@ func ServePizzaAdmit(w http.ResponseWriter, req *http.Request) {
 		review, ok := obj.(*admissionv1.AdmissionReview)
 		if !ok {
 			msg := fmt.Sprintf("Expected a v1beta1.AdmissionReview but got: %T", obj)
-			klog.Errorf(msg)
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
 			http.Error(w, msg, http.StatusBadRequest)
 			return
 		}
 		if review.Request == nil {
 			msg := "unexpected nil request"
-			klog.Errorf(msg)
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
 			http.Error(w, msg, http.StatusBadRequest)
+			return
 		}
-		review.Response = doAdmitV1(review)
+		ctx := withRequestLogger(req.Context(), review.Request.UID, review.Request.Namespace, review.Request.Name, review.Request.Operation)
+		review.Response = doAdmitV1(ctx, review, recipeLister)
 		review.Request = &admissionv1.AdmissionRequest{}
 		responseObj = review
 	case admissionv1beta1.SchemeGroupVersion.WithKind("AdmissionReview"):
 		review, ok := obj.(*admissionv1beta1.AdmissionReview)
 		if !ok {
 			msg := fmt.Sprintf("Expected a v1beta1.AdmissionReview but got: %T", obj)
-			klog.Errorf(msg)
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
 			http.Error(w, msg, http.StatusBadRequest)
 			return
 		}
 		if review.Request == nil {
 			msg := "unexpected nil request"
-			klog.Errorf(msg)
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
 			http.Error(w, msg, http.StatusBadRequest)
+			return
 		}
-		review.Response = doAdmitV1beta1(review)
+		ctx := withRequestLogger(req.Context(), review.Request.UID, review.Request.Namespace, review.Request.Name, review.Request.Operation)
+		review.Response = doAdmitV1beta1(ctx, review, recipeLister)
 		review.Request = &admissionv1beta1.AdmissionRequest{}
 		responseObj = review
 	default:
 		msg := fmt.Sprintf("unexpected GroupVersionKind: %v", gvk)
-		klog.Errorf(msg)
+		logger.Error(nil, "Rejecting malformed request", "reason", msg)
 		http.Error(w, msg, http.StatusBadRequest)
 		return
 	}
 	webhook.SendResponse(w, req, responseObj)
 }
 
-func doAdmitV1(review *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+func doAdmitV1(ctx context.Context, review *admissionv1.AdmissionReview, recipeLister restaurantv1beta2.PizzaRecipeLister) *admissionv1.AdmissionResponse {
 	response := &admissionv1.AdmissionResponse{
 		UID: review.Request.UID,
 	}
 	var err error
 	if review.Request.Object.Object == nil {
-		review.Request.Object.Object, _, err = webhook.Codecs.UniversalDeserializer().Decode(review.Request.Object.Raw, nil, nil)
+		review.Request.Object.Object, _, err = webhook.Decode(ctx, webhook.Codecs.UniversalDeserializer(), review.Request.Object.Raw)
+		if err != nil {
+			response.Result = &metav1.Status{
+				Message: err.Error(),
+				Status:  metav1.StatusFailure,
+			}
+			klog.FromContext(ctx).Error(err, "Failed to decode pizza")
+			return response
+		}
+	}
+	if review.Request.OldObject.Object == nil && len(review.Request.OldObject.Raw) > 0 {
+		review.Request.OldObject.Object, _, err = webhook.Decode(ctx, webhook.Codecs.UniversalDeserializer(), review.Request.OldObject.Raw)
 		if err != nil {
 			response.Result = &metav1.Status{
 				Message: err.Error(),
 				Status:  metav1.StatusFailure,
 			}
+			klog.FromContext(ctx).Error(err, "Failed to decode old pizza")
 			return response
 		}
 	}
 	orig := review.Request.Object.Raw
-	patch, err := patchPizza(orig, review.Request.Object.Object, review.Request.Namespace, review.Request.Name, review.GroupVersionKind())
+	patch, err := patchPizza(ctx, orig, review.Request.Object.Object, review.Request.OldObject.Object, review.Request.Namespace, recipeLister)
 	if err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to default pizza")
 		response.Result = &metav1.Status{
 			Message: err.Error(),
 			Status:  metav1.StatusFailure,
@@ -109,24 +154,37 @@ func doAdmitV1(review *admissionv1.AdmissionReview) *admissionv1.AdmissionRespon
 	return response
 }
 
-func doAdmitV1beta1(review *admissionv1beta1.AdmissionReview) *admissionv1beta1.AdmissionResponse {
+func doAdmitV1beta1(ctx context.Context, review *admissionv1beta1.AdmissionReview, recipeLister restaurantv1beta2.PizzaRecipeLister) *admissionv1beta1.AdmissionResponse {
 	response := &admissionv1beta1.AdmissionResponse{
 		UID: review.Request.UID,
 	}
 	var err error
 	if review.Request.Object.Object == nil {
-		review.Request.Object.Object, _, err = webhook.Codecs.UniversalDeserializer().Decode(review.Request.Object.Raw, nil, nil)
+		review.Request.Object.Object, _, err = webhook.Decode(ctx, webhook.Codecs.UniversalDeserializer(), review.Request.Object.Raw)
+		if err != nil {
+			response.Result = &metav1.Status{
+				Message: err.Error(),
+				Status:  metav1.StatusFailure,
+			}
+			klog.FromContext(ctx).Error(err, "Failed to decode pizza")
+			return response
+		}
+	}
+	if review.Request.OldObject.Object == nil && len(review.Request.OldObject.Raw) > 0 {
+		review.Request.OldObject.Object, _, err = webhook.Decode(ctx, webhook.Codecs.UniversalDeserializer(), review.Request.OldObject.Raw)
 		if err != nil {
 			response.Result = &metav1.Status{
 				Message: err.Error(),
 				Status:  metav1.StatusFailure,
 			}
+			klog.FromContext(ctx).Error(err, "Failed to decode old pizza")
 			return response
 		}
 	}
 	orig := review.Request.Object.Raw
-	patch, err := patchPizza(orig, review.Request.Object.Object, review.Request.Namespace, review.Request.Name, review.GroupVersionKind())
+	patch, err := patchPizza(ctx, orig, review.Request.Object.Object, review.Request.OldObject.Object, review.Request.Namespace, recipeLister)
 	if err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to default pizza")
 		response.Result = &metav1.Status{
 			Message: err.Error(),
 			Status:  metav1.StatusFailure,
@@ -140,12 +198,20 @@ func doAdmitV1beta1(review *admissionv1beta1.AdmissionReview) *admissionv1beta1.
 	return response
 }
 
-func patchPizza(orig []byte, pizza runtime.Object, namespace string, name string, gvk schema.GroupVersionKind) ([]byte, error) {
-	bs, err := defaultingPizza(pizza)
+func patchPizza(ctx context.Context, orig []byte, pizza, oldPizza runtime.Object, namespace string, recipeLister restaurantv1beta2.PizzaRecipeLister) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("not defaulting pizza: %v", err)
+	}
+	if err := MutatePizza(ctx, pizza, oldPizza, namespace, recipeLister); err != nil {
+		return nil, err
+	}
+	bs, err := json.Marshal(pizza)
 	if err != nil {
 		return nil, err
 	}
-	klog.V(2).Infof("Defaulting %s/%s in version %s", namespace, name, gvk)
+	klog.FromContext(ctx).V(2).Info("Defaulting pizza", "version", pizza.GetObjectKind().GroupVersionKind().Version)
+	_, span := tracing.Start(ctx, "Create patch")
+	defer span.End(webhook.TraceThreshold)
 	ops, err := jsonpatch.CreatePatch(orig, bs)
 	if err != nil {
 		return nil, err
@@ -153,34 +219,130 @@ func patchPizza(orig []byte, pizza runtime.Object, namespace string, name string
 	return json.Marshal(ops)
 }
 
-func defaultingPizza(pizza runtime.Object) ([]byte, error) {
-	var bs []byte
-	var err error
+// MutatePizza applies the changes the mutating webhook makes to a Pizza in
+// namespace: it expands the recipe of the pizza and sets the defaults.
+// oldPizza is nil on create. Recipes are looked up through recipeLister,
+// which may be backed by an informer or by a local catalog.
+func MutatePizza(ctx context.Context, pizza, oldPizza runtime.Object, namespace string, recipeLister restaurantv1beta2.PizzaRecipeLister) error {
+	expandCtx, span := tracing.Start(ctx, "Expand recipe")
+	err := expandRecipe(expandCtx, pizza, oldPizza, namespace, recipeLister)
+	span.End(webhook.TraceThreshold)
+	if err != nil {
+		return err
+	}
+	_, span = tracing.Start(ctx, "Default pizza")
+	defer span.End(webhook.TraceThreshold)
+	return DefaultPizza(pizza)
+}
+
+// expandRecipe writes the toppings of the recipe of a pizza into
+// spec.toppings if the recipe has the Expand mode. This happens on create and
+// when recipeRef, additions or removals change, so that later changes of the
+// recipe only reach the pizza through the recipe controller. It fails if the
+// recipe does not exist.
+func expandRecipe(ctx context.Context, pizza, oldPizza runtime.Object, namespace string, recipeLister restaurantv1beta2.PizzaRecipeLister) error {
+	hubObj, err := conversion.Convert(pizza, v1beta2.SchemeGroupVersion.String())
+	if err != nil {
+		return err
+	}
+	hub := hubObj.(*v1beta2.Pizza)
+	if hub.Spec.RecipeRef == nil {
+		return nil
+	}
+	if oldPizza != nil {
+		oldHub, err := conversion.Convert(oldPizza, v1beta2.SchemeGroupVersion.String())
+		if err != nil {
+			return err
+		}
+		if recipeFieldsEqual(&hub.Spec, &oldHub.(*v1beta2.Pizza).Spec) {
+			return nil
+		}
+	}
+
+	recipe, err := getRecipe(ctx, recipeLister, namespace, hub.Spec.RecipeRef.Name)
+	if errors.IsNotFound(err) {
+		return fmt.Errorf("pizza recipe %q not found", hub.Spec.RecipeRef.Name)
+	} else if err != nil {
+		return err
+	}
+	if recipe.EffectiveMode() != v1beta2.RecipeExpand {
+		return nil
+	}
+	klog.FromContext(ctx).V(2).Info("Expanding recipe", "recipe", klog.KObj(recipe), "generation", recipe.Generation)
+	return setToppings(pizza, recipe.Expand(hub.Spec.Additions, hub.Spec.Removals))
+}
+
+// recipeFieldsEqual returns whether recipeRef, additions and removals of two
+// pizzas are equal.
+func recipeFieldsEqual(a, b *v1beta2.PizzaSpec) bool {
+	return apiequality.Semantic.DeepEqual(a.RecipeRef, b.RecipeRef) &&
+		apiequality.Semantic.DeepEqual(a.Additions, b.Additions) &&
+		apiequality.Semantic.DeepEqual(a.Removals, b.Removals)
+}
+
+// setToppings sets the toppings of the whole pizza of any version. In the
+// versions without spec.sections, the toppings of the sections are kept in
+// spec.toppings.
+func setToppings(pizza runtime.Object, toppings []v1beta2.PizzaTopping) error {
+	hubObj, err := conversion.Convert(pizza, v1beta2.SchemeGroupVersion.String())
+	if err != nil {
+		return err
+	}
+	hub := hubObj.(*v1beta2.Pizza)
+	hub.Spec.Toppings = toppings
+	out, err := conversion.Convert(hub, pizza.GetObjectKind().GroupVersionKind().GroupVersion().String())
+	if err != nil {
+		return err
+	}
+	switch p := pizza.(type) {
+	case *v1alpha1.Pizza:
+		p.Spec.Toppings = out.(*v1alpha1.Pizza).Spec.Toppings
+	case *v1beta1.Pizza:
+		p.Spec.Toppings = out.(*v1beta1.Pizza).Spec.Toppings
+	case *v1beta2.Pizza:
+		p.Spec.Toppings = out.(*v1beta2.Pizza).Spec.Toppings
+	}
+	return nil
+}
+
+// DefaultPizza sets the defaults the mutating webhook applies to a Pizza.
+// Pizzas with a recipe get no default toppings.
+func DefaultPizza(pizza runtime.Object) error {
 	switch p := pizza.(type) {
 	case *v1alpha1.Pizza:
 		// default toppings
-		if len(p.Spec.Toppings) == 0 {
+		if len(p.Spec.Toppings) == 0 && p.Spec.RecipeRef == nil {
 			p.Spec.Toppings = []string{"tomato", "mozzarella", "salami"}
 		}
-		bs, err = json.Marshal(p)
-		if err != nil {
-			return nil, err
-		}
-		return bs, nil
+		return nil
 	case *v1beta1.Pizza:
-		if len(p.Spec.Toppings) == 0 {
+		if len(p.Spec.Toppings) == 0 && p.Spec.RecipeRef == nil {
 			p.Spec.Toppings = []v1beta1.PizzaTopping{
 				{Name: "tomato", Quantity: 1},
 				{Name: "mozzarella", Quantity: 1},
 				{Name: "salami", Quantity: 1},
 			}
 		}
-		bs, err = json.Marshal(p)
-		if err != nil {
-			return nil, err
+		return nil
+	case *v1beta2.Pizza:
+		if len(p.Spec.Toppings) == 0 && len(p.Spec.Sections) == 0 && p.Spec.RecipeRef == nil {
+			p.Spec.Toppings = []v1beta2.PizzaTopping{
+				{Name: "tomato", Quantity: 1},
+				{Name: "mozzarella", Quantity: 1},
+				{Name: "salami", Quantity: 1},
+			}
+		}
+		if len(p.Spec.Size) == 0 {
+			p.Spec.Size = v1beta2.PizzaSize(v1alpha1.DefaultPizzaSize)
 		}
-		return bs, nil
+		return nil
 	default:
-		return nil, fmt.Errorf("unexpected type %T", pizza)
+		return fmt.Errorf("unexpected type %T", pizza)
 	}
 }
+
+// withRequestLogger returns a context whose logger identifies the admission request.
+func withRequestLogger(ctx context.Context, uid types.UID, namespace, name string, operation interface{}) context.Context {
+	ctx, _ = webhook.WithReviewLogger(ctx, "uid", uid, "namespace", namespace, "name", name, "operation", operation)
+	return ctx
+}
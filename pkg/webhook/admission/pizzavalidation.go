@@ -1,43 +1,62 @@
 package admission
 
 import (
+	"context"
 	"fmt"
-	"io"
+	"math"
 	"net/http"
+	"time"
 
 	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
 	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
 	restaurantinformers "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions"
 	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1alpha1"
+	restaurantv1beta2 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1beta2"
 	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	"github.com/zeroisme/pizza-crd/pkg/webhook/conversion"
+	"go.opentelemetry.io/otel/attribute"
 	admissionv1 "k8s.io/api/admission/v1"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/tracing"
 	"k8s.io/klog/v2"
 )
 
 func ServePizzaValidation(informers restaurantinformers.SharedInformerFactory) func(http.ResponseWriter, *http.Request) {
 	toppingInformer := informers.Restaurant().V1alpha1().Toppings().Informer()
 	toppingLister := informers.Restaurant().V1alpha1().Toppings().Lister()
+	recipeInformer := informers.Restaurant().V1beta2().PizzaRecipes().Informer()
+	recipeLister := informers.Restaurant().V1beta2().PizzaRecipes().Lister()
 
 	return func(w http.ResponseWriter, req *http.Request) {
-		if !toppingInformer.HasSynced() {
-			http.Error(w, "topping informer not synced yet", http.StatusInternalServerError)
+		logger := klog.FromContext(req.Context())
+
+		body, err := webhook.ReadBody(req)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read request body: %v", err), webhook.ReadBodyErrorCode(err))
 			return
 		}
 
-		body, err := io.ReadAll(req.Body)
-		if err != nil {
-			http.Error(w, "failed to read request body", http.StatusBadRequest)
+		// wait for the toppings and recipes until the request deadline
+		_, span := tracing.Start(req.Context(), "Wait for topping and recipe informers")
+		synced := cache.WaitForCacheSync(req.Context().Done(), toppingInformer.HasSynced, recipeInformer.HasSynced)
+		span.End(webhook.TraceThreshold)
+		if !synced {
+			http.Error(w, "topping and recipe informers not synced yet", http.StatusInternalServerError)
 			return
 		}
 
-		obj, gvk, err := webhook.Codecs.UniversalDeserializer().Decode(body, nil, nil)
+		webhook.LogBody(logger, "Handling request", body)
+		obj, gvk, err := webhook.Decode(req.Context(), webhook.Codecs.UniversalDeserializer(), body)
 		if err != nil {
-			msg := fmt.Sprintf("failed to deserialize body (%v) with error %v", string(body), err)
-			klog.Error(err)
+			msg := fmt.Sprintf("failed to deserialize body of %d bytes: %v", len(body), err)
+			logger.Error(err, "Failed to deserialize request body", "size", len(body))
 			http.Error(w, msg, http.StatusBadRequest)
 			return
 		}
@@ -47,37 +66,41 @@ func ServePizzaValidation(informers restaurantinformers.SharedInformerFactory) f
 			review, ok := obj.(*admissionv1.AdmissionReview)
 			if !ok {
 				msg := fmt.Sprintf("Expected a v1beta1.AdmissionReview but got: %T", obj)
-				klog.Errorf(msg)
+				logger.Error(nil, "Rejecting malformed request", "reason", msg)
 				http.Error(w, msg, http.StatusBadRequest)
 				return
 			}
 			if review.Request == nil {
 				msg := "unexpected nil request"
-				klog.Errorf(msg)
+				logger.Error(nil, "Rejecting malformed request", "reason", msg)
 				http.Error(w, msg, http.StatusBadRequest)
+				return
 			}
-			review.Response = doValidateV1(review, toppingLister)
+			ctx := withRequestLogger(req.Context(), review.Request.UID, review.Request.Namespace, review.Request.Name, review.Request.Operation)
+			review.Response = doValidateV1(ctx, review, toppingLister, recipeLister)
 			review.Request = &admissionv1.AdmissionRequest{}
 			responseObj = review
 		case admissionv1beta1.SchemeGroupVersion.WithKind("AdmissionReview"):
 			review, ok := obj.(*admissionv1beta1.AdmissionReview)
 			if !ok {
 				msg := fmt.Sprintf("Expected a v1beta1.AdmissionReview but got: %T", obj)
-				klog.Errorf(msg)
+				logger.Error(nil, "Rejecting malformed request", "reason", msg)
 				http.Error(w, msg, http.StatusBadRequest)
 				return
 			}
 			if review.Request == nil {
 				msg := "unexpected nil request"
-				klog.Errorf(msg)
+				logger.Error(nil, "Rejecting malformed request", "reason", msg)
 				http.Error(w, msg, http.StatusBadRequest)
+				return
 			}
-			review.Response = doValidateV1beta1(review, toppingLister)
+			ctx := withRequestLogger(req.Context(), review.Request.UID, review.Request.Namespace, review.Request.Name, review.Request.Operation)
+			review.Response = doValidateV1beta1(ctx, review, toppingLister, recipeLister)
 			review.Request = &admissionv1beta1.AdmissionRequest{}
 			responseObj = review
 		default:
 			msg := fmt.Sprintf("unexpected GroupVersionKind: %v", gvk)
-			klog.Errorf(msg)
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
 			http.Error(w, msg, http.StatusBadRequest)
 			return
 		}
@@ -85,24 +108,37 @@ func ServePizzaValidation(informers restaurantinformers.SharedInformerFactory) f
 	}
 }
 
-func doValidateV1(review *admissionv1.AdmissionReview, toppingLister restaurantv1alpha1.ToppingLister) *admissionv1.AdmissionResponse {
+func doValidateV1(ctx context.Context, review *admissionv1.AdmissionReview, toppingLister restaurantv1alpha1.ToppingLister, recipeLister restaurantv1beta2.PizzaRecipeLister) *admissionv1.AdmissionResponse {
 	response := &admissionv1.AdmissionResponse{
 		UID: review.Request.UID,
 	}
 	var err error
 
 	if review.Request.Object.Object == nil {
-		review.Request.Object.Object, _, err = webhook.Codecs.UniversalDeserializer().Decode(review.Request.Object.Raw, nil, nil)
+		review.Request.Object.Object, _, err = webhook.Decode(ctx, webhook.Codecs.UniversalDeserializer(), review.Request.Object.Raw)
 		if err != nil {
 			response.Result = &metav1.Status{
 				Message: err.Error(),
 				Status:  metav1.StatusFailure,
 			}
+			klog.FromContext(ctx).Error(err, "Failed to decode pizza")
 			return response
 		}
 	}
-	err = validatePizza(review.Request.Object.Object, toppingLister)
+	if review.Request.OldObject.Object == nil && len(review.Request.OldObject.Raw) > 0 {
+		review.Request.OldObject.Object, _, err = webhook.Decode(ctx, webhook.Codecs.UniversalDeserializer(), review.Request.OldObject.Raw)
+		if err != nil {
+			response.Result = &metav1.Status{
+				Message: err.Error(),
+				Status:  metav1.StatusFailure,
+			}
+			klog.FromContext(ctx).Error(err, "Failed to decode old pizza")
+			return response
+		}
+	}
+	response.Warnings, err = validatePizza(ctx, review.Request.Object.Object, review.Request.OldObject.Object, toppingLister, recipeLister)
 	if err != nil {
+		klog.FromContext(ctx).V(2).Info("Rejecting invalid pizza", "err", err)
 		response.Result = &metav1.Status{
 			Message: err.Error(),
 			Status:  metav1.StatusFailure,
@@ -118,24 +154,37 @@ func doValidateV1(review *admissionv1.AdmissionReview, toppingLister restaurantv
 	return response
 }
 
-func doValidateV1beta1(review *admissionv1beta1.AdmissionReview, toppingLister restaurantv1alpha1.ToppingLister) *admissionv1beta1.AdmissionResponse {
+func doValidateV1beta1(ctx context.Context, review *admissionv1beta1.AdmissionReview, toppingLister restaurantv1alpha1.ToppingLister, recipeLister restaurantv1beta2.PizzaRecipeLister) *admissionv1beta1.AdmissionResponse {
 	response := &admissionv1beta1.AdmissionResponse{
 		UID: review.Request.UID,
 	}
 	var err error
 
 	if review.Request.Object.Object == nil {
-		review.Request.Object.Object, _, err = webhook.Codecs.UniversalDeserializer().Decode(review.Request.Object.Raw, nil, nil)
+		review.Request.Object.Object, _, err = webhook.Decode(ctx, webhook.Codecs.UniversalDeserializer(), review.Request.Object.Raw)
+		if err != nil {
+			response.Result = &metav1.Status{
+				Message: err.Error(),
+				Status:  metav1.StatusFailure,
+			}
+			klog.FromContext(ctx).Error(err, "Failed to decode pizza")
+			return response
+		}
+	}
+	if review.Request.OldObject.Object == nil && len(review.Request.OldObject.Raw) > 0 {
+		review.Request.OldObject.Object, _, err = webhook.Decode(ctx, webhook.Codecs.UniversalDeserializer(), review.Request.OldObject.Raw)
 		if err != nil {
 			response.Result = &metav1.Status{
 				Message: err.Error(),
 				Status:  metav1.StatusFailure,
 			}
+			klog.FromContext(ctx).Error(err, "Failed to decode old pizza")
 			return response
 		}
 	}
-	err = validatePizza(review.Request.Object.Object, toppingLister)
+	response.Warnings, err = validatePizza(ctx, review.Request.Object.Object, review.Request.OldObject.Object, toppingLister, recipeLister)
 	if err != nil {
+		klog.FromContext(ctx).V(2).Info("Rejecting invalid pizza", "err", err)
 		response.Result = &metav1.Status{
 			Message: err.Error(),
 			Status:  metav1.StatusFailure,
@@ -151,27 +200,457 @@ func doValidateV1beta1(review *admissionv1beta1.AdmissionReview, toppingLister r
 	return response
 }
 
-func validatePizza(pizzaObj runtime.Object, toppingLister restaurantv1alpha1.ToppingLister) error {
+// validatePizza validates a Pizza, that none of the toppings added to it is
+// retired and that enough stock of its toppings is available. oldPizzaObj is
+// nil on create. It returns warnings about deprecated and retired toppings.
+func validatePizza(ctx context.Context, pizzaObj, oldPizzaObj runtime.Object, toppingLister restaurantv1alpha1.ToppingLister, recipeLister restaurantv1beta2.PizzaRecipeLister) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "Validate pizza")
+	defer span.End(webhook.TraceThreshold)
+	allErrs := ValidatePizza(ctx, pizzaObj, toppingLister, recipeLister)
+	if len(allErrs) > 0 {
+		return nil, allErrs.ToAggregate()
+	}
+	recipe, err := getPizzaRecipe(ctx, pizzaObj, recipeLister)
+	if err != nil {
+		return nil, err
+	}
+	// the recipe of the old pizza may be gone, then its toppings count as
+	// added by the update
+	oldRecipe, _ := getPizzaRecipe(ctx, oldPizzaObj, recipeLister)
+	warnings, allErrs := validateLifecycle(ctx, pizzaObj, recipe, oldPizzaObj, oldRecipe, toppingLister, time.Now())
+	allErrs = append(allErrs, validateStock(ctx, pizzaObj, recipe, oldPizzaObj, oldRecipe, toppingLister)...)
+	return warnings, allErrs.ToAggregate()
+}
+
+// ValidatePizza checks that a Pizza has toppings, sections or a recipe, that
+// all its toppings and additions exist, that the pizza satisfies the
+// exclusions, requirements and maximum quantities the toppings declare, that
+// all toppings support its dietary claims and are offered for its size, and
+// that its sections cover the whole pizza. The toppings of a recipe with the
+// Resolve mode count as toppings of the pizza. Toppings and recipes are looked
+// up through toppingLister and recipeLister, which may be backed by informers
+// or by a local catalog.
+func ValidatePizza(ctx context.Context, pizzaObj runtime.Object, toppingLister restaurantv1alpha1.ToppingLister, recipeLister restaurantv1beta2.PizzaRecipeLister) field.ErrorList {
+	var allErrs field.ErrorList
+	recipe, err := getPizzaRecipe(ctx, pizzaObj, recipeLister)
+	if errors.IsNotFound(err) {
+		return append(allErrs, field.NotFound(field.NewPath("spec", "recipeRef", "name"), recipeName(pizzaObj)))
+	} else if err != nil {
+		return append(allErrs, field.InternalError(field.NewPath("spec", "recipeRef", "name"), err))
+	}
+	fldPath := field.NewPath("spec", "toppings")
+	additionsPath := field.NewPath("spec", "additions")
 	switch pizza := pizzaObj.(type) {
 	case *v1alpha1.Pizza:
-		for _, topping := range pizza.Spec.Toppings {
-			if _, err := toppingLister.Get(topping); err != nil && !errors.IsNotFound(err) {
-				return fmt.Errorf("failed to lookup topping %q: %v", topping, err)
-			} else if errors.IsNotFound(err) {
-				return fmt.Errorf("topping %q not found", topping)
+		if len(pizza.Spec.Toppings) == 0 && pizza.Spec.RecipeRef == nil {
+			allErrs = append(allErrs, field.Required(fldPath, "a pizza needs toppings or a recipeRef"))
+		}
+		for i, topping := range pizza.Spec.Toppings {
+			allErrs = append(allErrs, validateTopping(ctx, fldPath.Index(i), topping, toppingLister)...)
+		}
+		for i, topping := range pizza.Spec.Additions {
+			allErrs = append(allErrs, validateTopping(ctx, additionsPath.Index(i), topping, toppingLister)...)
+		}
+	case *v1beta1.Pizza:
+		if len(pizza.Spec.Toppings) == 0 && pizza.Spec.RecipeRef == nil {
+			allErrs = append(allErrs, field.Required(fldPath, "a pizza needs toppings or a recipeRef"))
+		}
+		for i, topping := range pizza.Spec.Toppings {
+			allErrs = append(allErrs, validateTopping(ctx, fldPath.Index(i).Child("name"), topping.Name, toppingLister)...)
+		}
+		for i, topping := range pizza.Spec.Additions {
+			allErrs = append(allErrs, validateTopping(ctx, additionsPath.Index(i).Child("name"), topping.Name, toppingLister)...)
+		}
+	case *v1beta2.Pizza:
+		if len(pizza.Spec.Toppings) == 0 && len(pizza.Spec.Sections) == 0 && pizza.Spec.RecipeRef == nil {
+			allErrs = append(allErrs, field.Required(fldPath, "a pizza needs toppings, sections or a recipeRef"))
+		}
+		for i, topping := range pizza.Spec.Toppings {
+			allErrs = append(allErrs, validateTopping(ctx, fldPath.Index(i).Child("name"), topping.Name, toppingLister)...)
+		}
+		for i, topping := range pizza.Spec.Additions {
+			allErrs = append(allErrs, validateTopping(ctx, additionsPath.Index(i).Child("name"), topping.Name, toppingLister)...)
+		}
+		for i, section := range pizza.Spec.Sections {
+			sectionPath := field.NewPath("spec", "sections").Index(i).Child("toppings")
+			for j, topping := range section.Toppings {
+				allErrs = append(allErrs, validateTopping(ctx, sectionPath.Index(j).Child("name"), topping.Name, toppingLister)...)
 			}
 		}
-		return nil
+	default:
+		return append(allErrs, field.InternalError(nil, fmt.Errorf("unexpected pizza type: %T", pizzaObj)))
+	}
+	if recipe != nil && recipe.EffectiveMode() == v1beta2.RecipeResolve {
+		recipePath := field.NewPath("spec", "recipeRef", "name")
+		for _, topping := range recipe.Expand(nil, removals(pizzaObj)) {
+			allErrs = append(allErrs, validateTopping(ctx, recipePath, topping.Name, toppingLister)...)
+		}
+	}
+	allErrs = append(allErrs, validateToppingRules(ctx, pizzaObj, recipe, toppingLister)...)
+	allErrs = append(allErrs, validateDietaryClaims(ctx, pizzaObj, recipe, toppingLister)...)
+	allErrs = append(allErrs, validateSize(ctx, pizzaObj, recipe, toppingLister)...)
+	return append(allErrs, validateSections(pizzaObj)...)
+}
+
+// getPizzaRecipe returns the recipe a pizza refers to, or nil if the pizza is
+// nil or has no recipe.
+func getPizzaRecipe(ctx context.Context, pizzaObj runtime.Object, recipeLister restaurantv1beta2.PizzaRecipeLister) (*v1beta2.PizzaRecipe, error) {
+	name := recipeName(pizzaObj)
+	if len(name) == 0 {
+		return nil, nil
+	}
+	return getRecipe(ctx, recipeLister, pizzaObj.(metav1.Object).GetNamespace(), name)
+}
+
+func getRecipe(ctx context.Context, recipeLister restaurantv1beta2.PizzaRecipeLister, namespace, name string) (*v1beta2.PizzaRecipe, error) {
+	_, span := tracing.Start(ctx, "Lookup recipe", attribute.String("recipe", name))
+	defer span.End(webhook.TraceThreshold)
+	return recipeLister.PizzaRecipes(namespace).Get(name)
+}
+
+// removals returns the recipe toppings removed from a pizza.
+func removals(pizzaObj runtime.Object) []string {
+	switch pizza := pizzaObj.(type) {
+	case *v1alpha1.Pizza:
+		return pizza.Spec.Removals
 	case *v1beta1.Pizza:
-		for _, topping := range pizza.Spec.Toppings {
-			if _, err := toppingLister.Get(topping.Name); err != nil && !errors.IsNotFound(err) {
-				return fmt.Errorf("failed to lookup topping %q: %v", topping, err)
-			} else if errors.IsNotFound(err) {
-				return fmt.Errorf("topping %q not found", topping.Name)
+		return pizza.Spec.Removals
+	case *v1beta2.Pizza:
+		return pizza.Spec.Removals
+	}
+	return nil
+}
+
+// recipeName returns the name of the recipe a pizza refers to, or the empty
+// string.
+func recipeName(pizzaObj runtime.Object) string {
+	switch pizza := pizzaObj.(type) {
+	case *v1alpha1.Pizza:
+		if pizza.Spec.RecipeRef != nil {
+			return pizza.Spec.RecipeRef.Name
+		}
+	case *v1beta1.Pizza:
+		if pizza.Spec.RecipeRef != nil {
+			return pizza.Spec.RecipeRef.Name
+		}
+	case *v1beta2.Pizza:
+		if pizza.Spec.RecipeRef != nil {
+			return pizza.Spec.RecipeRef.Name
+		}
+	}
+	return ""
+}
+
+func validateTopping(ctx context.Context, fldPath *field.Path, name string, toppingLister restaurantv1alpha1.ToppingLister) field.ErrorList {
+	if err := ctx.Err(); err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	if _, err := getTopping(ctx, toppingLister, name); errors.IsNotFound(err) {
+		return field.ErrorList{field.NotFound(fldPath, name)}
+	} else if err != nil {
+		return field.ErrorList{field.InternalError(fldPath, fmt.Errorf("failed to lookup topping %q: %v", name, err))}
+	}
+	return nil
+}
+
+// validateToppingRules evaluates the rules of all toppings of a pizza against
+// its whole set of toppings. Toppings which do not exist are skipped.
+func validateToppingRules(ctx context.Context, pizzaObj runtime.Object, recipe *v1beta2.PizzaRecipe, toppingLister restaurantv1alpha1.ToppingLister) field.ErrorList {
+	quantities, paths := neededUnits(pizzaObj, recipe)
+
+	var allErrs field.ErrorList
+	for _, name := range sets.List(sets.KeySet(quantities)) {
+		topping, err := getTopping(ctx, toppingLister, name)
+		if err != nil {
+			continue
+		}
+		if max := topping.Spec.MaxQuantityPerPizza; max != nil && quantities[name] > int64(*max) {
+			allErrs = append(allErrs, field.Invalid(paths[name], quantities[name], fmt.Sprintf("topping %q may be put at most %d times onto a pizza", name, *max)))
+		}
+		for _, excluded := range topping.Spec.Excludes {
+			if quantities[excluded] > 0 {
+				allErrs = append(allErrs, field.Forbidden(paths[name], fmt.Sprintf("topping %q excludes topping %q", name, excluded)))
+			}
+		}
+		for _, required := range topping.Spec.Requires {
+			if quantities[required] == 0 {
+				allErrs = append(allErrs, field.Required(paths[name], fmt.Sprintf("topping %q requires topping %q", name, required)))
 			}
 		}
+	}
+	return allErrs
+}
+
+// validateDietaryClaims checks that every topping of a pizza supports each of
+// its dietary claims. Toppings which do not exist are skipped.
+func validateDietaryClaims(ctx context.Context, pizzaObj runtime.Object, recipe *v1beta2.PizzaRecipe, toppingLister restaurantv1alpha1.ToppingLister) field.ErrorList {
+	var claims []v1alpha1.DietaryClaim
+	switch pizza := pizzaObj.(type) {
+	case *v1alpha1.Pizza:
+		claims = pizza.Spec.DietaryClaims
+	case *v1beta1.Pizza:
+		for _, claim := range pizza.Spec.DietaryClaims {
+			claims = append(claims, v1alpha1.DietaryClaim(claim))
+		}
+	case *v1beta2.Pizza:
+		for _, claim := range pizza.Spec.DietaryClaims {
+			claims = append(claims, v1alpha1.DietaryClaim(claim))
+		}
+	}
+	if len(claims) == 0 {
 		return nil
-	default:
-		return fmt.Errorf("unexpected pizza type: %T", pizzaObj)
 	}
+	quantities, _ := neededUnits(pizzaObj, recipe)
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "dietaryClaims")
+	for _, name := range sets.List(sets.KeySet(quantities)) {
+		topping, err := getTopping(ctx, toppingLister, name)
+		if err != nil {
+			continue
+		}
+		for i, claim := range claims {
+			if !topping.Supports(claim) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i), claim, fmt.Sprintf("topping %q is not suitable", name)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateSize checks that the size of a pizza is known and that all of its
+// toppings are offered for the size. Toppings which do not exist are skipped.
+func validateSize(ctx context.Context, pizzaObj runtime.Object, recipe *v1beta2.PizzaRecipe, toppingLister restaurantv1alpha1.ToppingLister) field.ErrorList {
+	size := v1alpha1.DefaultPizzaSize
+	var fldPath *field.Path
+	switch pizza := pizzaObj.(type) {
+	case *v1alpha1.Pizza, *v1beta1.Pizza:
+		size = v1alpha1.SizeOf(pizza.(metav1.Object).GetAnnotations())
+		fldPath = field.NewPath("metadata", "annotations").Key(v1alpha1.SizeAnnotation)
+	case *v1beta2.Pizza:
+		if len(pizza.Spec.Size) > 0 {
+			size = v1alpha1.PizzaSize(pizza.Spec.Size)
+		}
+		fldPath = field.NewPath("spec", "size")
+	}
+	known := false
+	for _, s := range v1alpha1.PizzaSizes {
+		known = known || s == size
+	}
+	if !known {
+		validSizes := make([]string, 0, len(v1alpha1.PizzaSizes))
+		for _, s := range v1alpha1.PizzaSizes {
+			validSizes = append(validSizes, string(s))
+		}
+		return field.ErrorList{field.NotSupported(fldPath, size, validSizes)}
+	}
+
+	quantities, paths := neededUnits(pizzaObj, recipe)
+	var allErrs field.ErrorList
+	for _, name := range sets.List(sets.KeySet(quantities)) {
+		topping, err := getTopping(ctx, toppingLister, name)
+		if err != nil {
+			continue
+		}
+		if !topping.OffersSize(size) {
+			allErrs = append(allErrs, field.Forbidden(paths[name], fmt.Sprintf("topping %q is not offered for %s pizzas", name, size)))
+		}
+	}
+	return allErrs
+}
+
+// validateSections checks that the coverage of the sections of a pizza sums
+// up to 1. In the versions without spec.sections, the sections annotation has
+// to be valid and its toppings have to be part of spec.toppings.
+func validateSections(pizzaObj runtime.Object) field.ErrorList {
+	var sections []v1beta2.PizzaSection
+	var fldPath *field.Path
+	switch pizza := pizzaObj.(type) {
+	case *v1alpha1.Pizza, *v1beta1.Pizza:
+		annotations := pizza.(metav1.Object).GetAnnotations()
+		fldPath = field.NewPath("metadata", "annotations").Key(v1alpha1.SectionsAnnotation)
+		var err error
+		sections, err = conversion.SectionsOf(annotations)
+		if err != nil {
+			return field.ErrorList{field.Invalid(fldPath, annotations[v1alpha1.SectionsAnnotation], err.Error())}
+		}
+		if len(sections) == 0 {
+			return nil
+		}
+		hub, err := conversion.Convert(pizzaObj, v1beta2.SchemeGroupVersion.String())
+		if err != nil {
+			return field.ErrorList{field.InternalError(fldPath, err)}
+		}
+		if len(hub.(*v1beta2.Pizza).Spec.Sections) == 0 {
+			return field.ErrorList{field.Invalid(fldPath, annotations[v1alpha1.SectionsAnnotation], "the toppings of the sections are not part of spec.toppings")}
+		}
+	case *v1beta2.Pizza:
+		sections = pizza.Spec.Sections
+		fldPath = field.NewPath("spec", "sections")
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	names := sets.New[string]()
+	var coverage float64
+	for i, section := range sections {
+		if names.Has(section.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), section.Name))
+		}
+		names.Insert(section.Name)
+		if section.Coverage <= 0 || section.Coverage > 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("coverage"), section.Coverage, "must be greater than 0 and at most 1"))
+		}
+		coverage += section.Coverage
+	}
+	if math.Abs(coverage-1) > coverageTolerance {
+		allErrs = append(allErrs, field.Invalid(fldPath, coverage, "the coverage of all sections must sum up to 1"))
+	}
+	return allErrs
+}
+
+// coverageTolerance is the rounding error allowed in the sum of the coverage
+// of sections, e.g. for thirds written as 0.333 or 0.333333.
+const coverageTolerance = 1e-3
+
+func getTopping(ctx context.Context, toppingLister restaurantv1alpha1.ToppingLister, name string) (*v1alpha1.Topping, error) {
+	_, span := tracing.Start(ctx, "Lookup topping", attribute.String("topping", name))
+	defer span.End(webhook.TraceThreshold)
+	return toppingLister.Get(name)
+}
+
+// validateLifecycle forbids retired toppings which the pizza did not have
+// before, and warns about deprecated toppings and retired toppings the pizza
+// keeps.
+func validateLifecycle(ctx context.Context, pizzaObj runtime.Object, recipe *v1beta2.PizzaRecipe, oldPizzaObj runtime.Object, oldRecipe *v1beta2.PizzaRecipe, toppingLister restaurantv1alpha1.ToppingLister, now time.Time) ([]string, field.ErrorList) {
+	needed, paths := neededUnits(pizzaObj, recipe)
+	oldNeeded, _ := neededUnits(oldPizzaObj, oldRecipe)
+
+	var warnings []string
+	var allErrs field.ErrorList
+	for _, name := range sets.List(sets.KeySet(needed)) {
+		topping, err := getTopping(ctx, toppingLister, name)
+		if err != nil {
+			allErrs = append(allErrs, field.InternalError(paths[name], fmt.Errorf("failed to lookup topping %q: %v", name, err)))
+			continue
+		}
+		switch topping.EffectivePhase(now) {
+		case v1alpha1.ToppingDeprecated:
+			if topping.Spec.SunsetTime != nil {
+				warnings = append(warnings, fmt.Sprintf("topping %q is deprecated and will be retired at %s", name, topping.Spec.SunsetTime.UTC().Format(time.RFC3339)))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("topping %q is deprecated", name))
+			}
+		case v1alpha1.ToppingRetired:
+			if oldNeeded[name] == 0 {
+				allErrs = append(allErrs, field.Forbidden(paths[name], fmt.Sprintf("topping %q is retired", name)))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("topping %q is retired, it cannot be added to other pizzas", name))
+			}
+		}
+	}
+	return warnings, allErrs
+}
+
+// validateStock checks that the stock of every topping of which a pizza needs
+// more units than before is sufficient. Units already reserved for the pizza
+// count as available. The reservation itself is made by the stock controller.
+func validateStock(ctx context.Context, pizzaObj runtime.Object, recipe *v1beta2.PizzaRecipe, oldPizzaObj runtime.Object, oldRecipe *v1beta2.PizzaRecipe, toppingLister restaurantv1alpha1.ToppingLister) field.ErrorList {
+	pizza, ok := pizzaObj.(metav1.Object)
+	if !ok {
+		return field.ErrorList{field.InternalError(nil, fmt.Errorf("unexpected pizza type: %T", pizzaObj))}
+	}
+	key := pizza.GetNamespace() + "/" + pizza.GetName()
+	needed, paths := neededUnits(pizzaObj, recipe)
+	oldNeeded, _ := neededUnits(oldPizzaObj, oldRecipe)
+
+	var allErrs field.ErrorList
+	for _, name := range sets.List(sets.KeySet(needed)) {
+		if needed[name] <= oldNeeded[name] {
+			continue
+		}
+		topping, err := getTopping(ctx, toppingLister, name)
+		if err != nil {
+			allErrs = append(allErrs, field.InternalError(paths[name], fmt.Errorf("failed to lookup topping %q: %v", name, err)))
+			continue
+		}
+		available, limited := topping.Available()
+		if !limited {
+			continue
+		}
+		if available += topping.Status.Reservations[key]; needed[name] > available {
+			allErrs = append(allErrs, field.Forbidden(paths[name], fmt.Sprintf("needs %d units of topping %q, but only %d are in stock", needed[name], name, available)))
+		}
+	}
+	return allErrs
+}
+
+// neededUnits returns the units needed per topping of a pizza, i.e. its
+// quantity on the whole pizza and all sections, and the path of the first
+// entry of each topping. If the recipe of the pizza has the Resolve mode, its
+// toppings without the removals and with the additions are needed as well,
+// with the path of the addition or else of spec.recipeRef.name.
+func neededUnits(pizzaObj runtime.Object, recipe *v1beta2.PizzaRecipe) (map[string]int64, map[string]*field.Path) {
+	needed := map[string]int64{}
+	paths := map[string]*field.Path{}
+	fldPath := field.NewPath("spec", "toppings")
+	var additions []v1beta2.PizzaTopping
+	switch pizza := pizzaObj.(type) {
+	case *v1alpha1.Pizza:
+		for i, name := range pizza.Spec.Toppings {
+			if _, ok := paths[name]; !ok {
+				paths[name] = fldPath.Index(i)
+			}
+			needed[name]++
+		}
+		for _, name := range pizza.Spec.Additions {
+			additions = append(additions, v1beta2.PizzaTopping{Name: name, Quantity: 1})
+		}
+	case *v1beta1.Pizza:
+		for i, topping := range pizza.Spec.Toppings {
+			if _, ok := paths[topping.Name]; !ok {
+				paths[topping.Name] = fldPath.Index(i)
+			}
+			needed[topping.Name] += int64(topping.Quantity)
+		}
+		for _, topping := range pizza.Spec.Additions {
+			additions = append(additions, v1beta2.PizzaTopping{Name: topping.Name, Quantity: topping.Quantity})
+		}
+	case *v1beta2.Pizza:
+		additions = pizza.Spec.Additions
+		for i, topping := range pizza.Spec.Toppings {
+			if _, ok := paths[topping.Name]; !ok {
+				paths[topping.Name] = fldPath.Index(i)
+			}
+			needed[topping.Name] += int64(topping.Quantity)
+		}
+		for i, section := range pizza.Spec.Sections {
+			sectionPath := field.NewPath("spec", "sections").Index(i).Child("toppings")
+			for j, topping := range section.Toppings {
+				if _, ok := paths[topping.Name]; !ok {
+					paths[topping.Name] = sectionPath.Index(j)
+				}
+				needed[topping.Name] += int64(topping.Quantity)
+			}
+		}
+	}
+
+	if recipe == nil || recipe.EffectiveMode() != v1beta2.RecipeResolve {
+		return needed, paths
+	}
+	additionsPath := field.NewPath("spec", "additions")
+	for i, topping := range additions {
+		if _, ok := paths[topping.Name]; !ok {
+			paths[topping.Name] = additionsPath.Index(i)
+		}
+	}
+	for _, topping := range recipe.Expand(additions, removals(pizzaObj)) {
+		if _, ok := paths[topping.Name]; !ok {
+			paths[topping.Name] = field.NewPath("spec", "recipeRef", "name")
+		}
+		needed[topping.Name] += int64(topping.Quantity)
+	}
+	return needed, paths
 }
@@ -0,0 +1,164 @@
+package admission_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	webhooktesting "github.com/zeroisme/pizza-crd/pkg/webhook/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestValidatePizzaResolvedRecipe(t *testing.T) {
+	s := webhooktesting.NewServer(t, []runtime.Object{
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "tomato"}, Spec: v1alpha1.ToppingSpec{Cost: 0.5, Vegan: true}},
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "basil"}, Spec: v1alpha1.ToppingSpec{Cost: 0.3, Vegan: true}},
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "ham"}, Spec: v1alpha1.ToppingSpec{Cost: 1}},
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "olives"}, Spec: v1alpha1.ToppingSpec{Cost: 0.8, Vegan: true, MaxQuantityPerPizza: int32Ptr(2)}},
+		&v1beta2.PizzaRecipe{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "prosciutto"},
+			Spec: v1beta2.PizzaRecipeSpec{
+				Mode:     v1beta2.RecipeResolve,
+				Toppings: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}, {Name: "ham", Quantity: 1}, {Name: "olives", Quantity: 2}},
+			},
+		},
+	})
+	pizza := func(mutate func(*v1beta2.PizzaSpec)) *v1beta2.Pizza {
+		p := &v1beta2.Pizza{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "lunch"},
+			Spec: v1beta2.PizzaSpec{
+				RecipeRef: &v1beta2.PizzaRecipeReference{Name: "prosciutto"},
+			},
+		}
+		mutate(&p.Spec)
+		return p
+	}
+
+	tests := []struct {
+		name   string
+		pizza  *v1beta2.Pizza
+		denied string
+	}{
+		{
+			name:  "recipe",
+			pizza: pizza(func(*v1beta2.PizzaSpec) {}),
+		},
+		{
+			name: "vegan claim with ham of the recipe",
+			pizza: pizza(func(spec *v1beta2.PizzaSpec) {
+				spec.DietaryClaims = []v1beta2.DietaryClaim{v1beta2.DietaryClaimVegan}
+			}),
+			denied: `topping "ham" is not suitable`,
+		},
+		{
+			name: "vegan claim with ham removed",
+			pizza: pizza(func(spec *v1beta2.PizzaSpec) {
+				spec.DietaryClaims = []v1beta2.DietaryClaim{v1beta2.DietaryClaimVegan}
+				spec.Removals = []string{"ham"}
+			}),
+		},
+		{
+			name: "vegan claim with ham added",
+			pizza: pizza(func(spec *v1beta2.PizzaSpec) {
+				spec.DietaryClaims = []v1beta2.DietaryClaim{v1beta2.DietaryClaimVegan}
+				spec.Removals = []string{"ham"}
+				spec.Additions = []v1beta2.PizzaTopping{{Name: "basil", Quantity: 1}, {Name: "ham", Quantity: 1}}
+			}),
+			denied: `topping "ham" is not suitable`,
+		},
+		{
+			name: "addition exceeding the maximum quantity with the recipe",
+			pizza: pizza(func(spec *v1beta2.PizzaSpec) {
+				spec.Additions = []v1beta2.PizzaTopping{{Name: "olives", Quantity: 1}}
+			}),
+			denied: `spec.additions[0]: Invalid value: 3: topping "olives" may be put at most 2 times onto a pizza`,
+		},
+		{
+			name: "missing recipe",
+			pizza: pizza(func(spec *v1beta2.PizzaSpec) {
+				spec.RecipeRef.Name = "margherita"
+			}),
+			denied: `spec.recipeRef.name: Not found: "margherita"`,
+		},
+		{
+			name: "neither toppings nor recipe",
+			pizza: pizza(func(spec *v1beta2.PizzaSpec) {
+				spec.RecipeRef = nil
+			}),
+			denied: "spec.toppings: Required value: a pizza needs toppings",
+		},
+	}
+	for _, version := range webhooktesting.AdmissionReviewVersions {
+		for _, test := range tests {
+			t.Run(version+"/"+test.name, func(t *testing.T) {
+				result := s.Validate(t, webhooktesting.NewAdmissionReview(version, test.pizza).Build(t))
+				if len(test.denied) > 0 {
+					result.ExpectDenied(t, test.denied)
+				} else {
+					result.ExpectAllowed(t)
+				}
+			})
+		}
+	}
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestValidatePizzaSectionCoverage(t *testing.T) {
+	s := webhooktesting.NewServer(t, []runtime.Object{
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "tomato"}, Spec: v1alpha1.ToppingSpec{Cost: 0.5}},
+	})
+	// no whole-pizza toppings, i.e. no toppings key at all
+	pizza := func(coverages ...float64) *v1beta2.Pizza {
+		p := &v1beta2.Pizza{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sections"},
+			Spec:       v1beta2.PizzaSpec{Size: v1beta2.PizzaSizeMedium},
+		}
+		for i, coverage := range coverages {
+			p.Spec.Sections = append(p.Spec.Sections, v1beta2.PizzaSection{
+				Name:     fmt.Sprintf("section-%d", i),
+				Coverage: coverage,
+				Toppings: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}},
+			})
+		}
+		return p
+	}
+
+	tests := []struct {
+		name      string
+		coverages []float64
+		denied    string
+	}{
+		{name: "halves", coverages: []float64{0.5, 0.5}},
+		{name: "thirds with six digits", coverages: []float64{0.333333, 0.333333, 0.333333}},
+		{name: "thirds with three digits", coverages: []float64{0.333, 0.333, 0.333}},
+		{name: "thirds rounded up", coverages: []float64{0.334, 0.333, 0.333}},
+		{name: "thirds with two digits", coverages: []float64{0.33, 0.33, 0.33}, denied: "the coverage of all sections must sum up to 1"},
+		{name: "more than the pizza", coverages: []float64{0.5, 0.502}, denied: "the coverage of all sections must sum up to 1"},
+	}
+	for _, version := range webhooktesting.AdmissionReviewVersions {
+		t.Run(version+"/defaulting keeps the toppings empty", func(t *testing.T) {
+			halves := pizza(0.5, 0.5)
+			result := s.Admit(t, webhooktesting.NewAdmissionReview(version, halves).Build(t)).ExpectAllowed(t).ExpectPatchedTo(t, halves)
+			// defaulting must not add spec.toppings, not even as null
+			if strings.Contains(string(result.Response.Patch), "/spec/toppings") {
+				t.Errorf("unexpected patch of spec.toppings: %s", result.Response.Patch)
+			}
+		})
+		for _, test := range tests {
+			t.Run(version+"/"+test.name, func(t *testing.T) {
+				result := s.Validate(t, webhooktesting.NewAdmissionReview(version, pizza(test.coverages...)).Build(t))
+				if len(test.denied) > 0 {
+					result.ExpectDenied(t, test.denied)
+				} else {
+					result.ExpectAllowed(t)
+				}
+			})
+		}
+	}
+}
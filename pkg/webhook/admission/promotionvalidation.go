@@ -0,0 +1,148 @@
+package admission
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+)
+
+func ServePromotionValidation(w http.ResponseWriter, req *http.Request) {
+	logger := klog.FromContext(req.Context())
+
+	body, err := webhook.ReadBody(req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request body: %v", err), webhook.ReadBodyErrorCode(err))
+		return
+	}
+
+	webhook.LogBody(logger, "Handling request", body)
+	obj, gvk, err := webhook.Decode(req.Context(), webhook.Codecs.UniversalDeserializer(), body)
+	if err != nil {
+		msg := fmt.Sprintf("failed to deserialize body of %d bytes: %v", len(body), err)
+		logger.Error(err, "Failed to deserialize request body", "size", len(body))
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	var responseObj runtime.Object
+	switch *gvk {
+	case admissionv1.SchemeGroupVersion.WithKind("AdmissionReview"):
+		review, ok := obj.(*admissionv1.AdmissionReview)
+		if !ok {
+			msg := fmt.Sprintf("Expected a v1.AdmissionReview but got: %T", obj)
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		if review.Request == nil {
+			msg := "unexpected nil request"
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		ctx := withRequestLogger(req.Context(), review.Request.UID, review.Request.Namespace, review.Request.Name, review.Request.Operation)
+		allowed, result := doValidatePromotion(ctx, review.Request.Object.Raw)
+		review.Response = &admissionv1.AdmissionResponse{UID: review.Request.UID, Allowed: allowed, Result: result}
+		review.Request = &admissionv1.AdmissionRequest{}
+		responseObj = review
+	case admissionv1beta1.SchemeGroupVersion.WithKind("AdmissionReview"):
+		review, ok := obj.(*admissionv1beta1.AdmissionReview)
+		if !ok {
+			msg := fmt.Sprintf("Expected a v1beta1.AdmissionReview but got: %T", obj)
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		if review.Request == nil {
+			msg := "unexpected nil request"
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		ctx := withRequestLogger(req.Context(), review.Request.UID, review.Request.Namespace, review.Request.Name, review.Request.Operation)
+		allowed, result := doValidatePromotion(ctx, review.Request.Object.Raw)
+		review.Response = &admissionv1beta1.AdmissionResponse{UID: review.Request.UID, Allowed: allowed, Result: result}
+		review.Request = &admissionv1beta1.AdmissionRequest{}
+		responseObj = review
+	default:
+		msg := fmt.Sprintf("unexpected GroupVersionKind: %v", gvk)
+		logger.Error(nil, "Rejecting malformed request", "reason", msg)
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	webhook.SendResponse(w, req, responseObj)
+}
+
+// doValidatePromotion validates the raw promotion of a review.
+func doValidatePromotion(ctx context.Context, raw []byte) (bool, *metav1.Status) {
+	promotion, err := decodePromotion(ctx, raw)
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to decode promotion")
+		return false, &metav1.Status{Message: err.Error(), Status: metav1.StatusFailure}
+	}
+	if err := ValidatePromotion(promotion).ToAggregate(); err != nil {
+		klog.FromContext(ctx).V(2).Info("Rejecting invalid promotion", "err", err)
+		return false, &metav1.Status{Message: err.Error(), Status: metav1.StatusFailure}
+	}
+	return true, &metav1.Status{Message: "promotion is valid", Status: metav1.StatusSuccess}
+}
+
+func decodePromotion(ctx context.Context, raw []byte) (*v1beta2.Promotion, error) {
+	obj, _, err := webhook.Decode(ctx, webhook.Codecs.UniversalDeserializer(), raw)
+	if err != nil {
+		return nil, err
+	}
+	promotion, ok := obj.(*v1beta2.Promotion)
+	if !ok {
+		return nil, fmt.Errorf("unexpected promotion type: %T", obj)
+	}
+	return promotion, nil
+}
+
+// ValidatePromotion checks a Promotion: it needs exactly one discount, a
+// validity window which does not end before it starts and a valid pizza
+// selector.
+func ValidatePromotion(promotion *v1beta2.Promotion) field.ErrorList {
+	spec := promotion.Spec
+	specPath := field.NewPath("spec")
+	allErrs := validatePromotionDiscount(specPath.Child("discount"), spec.Discount)
+	if spec.ValidFrom != nil && spec.ValidUntil != nil && !spec.ValidUntil.After(spec.ValidFrom.Time) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("validUntil"), spec.ValidUntil, "must be after validFrom"))
+	}
+	allErrs = append(allErrs, metav1validation.ValidateLabelSelector(spec.Selector.PizzaSelector,
+		metav1validation.LabelSelectorValidationOptions{}, specPath.Child("selector", "pizzaSelector"))...)
+	return allErrs
+}
+
+func validatePromotionDiscount(fldPath *field.Path, discount v1beta2.PromotionDiscount) field.ErrorList {
+	var set []string
+	if discount.PercentOff != nil {
+		set = append(set, "percentOff")
+	}
+	if discount.AmountOff != nil {
+		set = append(set, "amountOff")
+	}
+	if discount.BuyNGetOneFree != nil {
+		set = append(set, "buyNGetOneFree")
+	}
+	switch {
+	case len(set) == 0:
+		return field.ErrorList{field.Required(fldPath, "one of percentOff, amountOff or buyNGetOneFree is required")}
+	case len(set) > 1:
+		var allErrs field.ErrorList
+		for _, name := range set[1:] {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child(name), fmt.Sprintf("may not be set together with %s", set[0])))
+		}
+		return allErrs
+	}
+	return nil
+}
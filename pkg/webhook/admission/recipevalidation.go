@@ -0,0 +1,177 @@
+package admission
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	restaurantinformers "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions"
+	restaurantv1alpha1 "github.com/zeroisme/pizza-crd/pkg/generated/listers/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/tracing"
+	"k8s.io/klog/v2"
+)
+
+func ServePizzaRecipeValidation(informers restaurantinformers.SharedInformerFactory) func(http.ResponseWriter, *http.Request) {
+	toppingInformer := informers.Restaurant().V1alpha1().Toppings().Informer()
+	toppingLister := informers.Restaurant().V1alpha1().Toppings().Lister()
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		logger := klog.FromContext(req.Context())
+
+		body, err := webhook.ReadBody(req)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read request body: %v", err), webhook.ReadBodyErrorCode(err))
+			return
+		}
+
+		// wait for the toppings until the request deadline
+		_, span := tracing.Start(req.Context(), "Wait for topping informer")
+		synced := cache.WaitForCacheSync(req.Context().Done(), toppingInformer.HasSynced)
+		span.End(webhook.TraceThreshold)
+		if !synced {
+			http.Error(w, "topping informer not synced yet", http.StatusInternalServerError)
+			return
+		}
+
+		webhook.LogBody(logger, "Handling request", body)
+		obj, gvk, err := webhook.Decode(req.Context(), webhook.Codecs.UniversalDeserializer(), body)
+		if err != nil {
+			msg := fmt.Sprintf("failed to deserialize body of %d bytes: %v", len(body), err)
+			logger.Error(err, "Failed to deserialize request body", "size", len(body))
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		var responseObj runtime.Object
+		switch *gvk {
+		case admissionv1.SchemeGroupVersion.WithKind("AdmissionReview"):
+			review, ok := obj.(*admissionv1.AdmissionReview)
+			if !ok {
+				msg := fmt.Sprintf("Expected a v1.AdmissionReview but got: %T", obj)
+				logger.Error(nil, "Rejecting malformed request", "reason", msg)
+				http.Error(w, msg, http.StatusBadRequest)
+				return
+			}
+			if review.Request == nil {
+				msg := "unexpected nil request"
+				logger.Error(nil, "Rejecting malformed request", "reason", msg)
+				http.Error(w, msg, http.StatusBadRequest)
+				return
+			}
+			ctx := withRequestLogger(req.Context(), review.Request.UID, review.Request.Namespace, review.Request.Name, review.Request.Operation)
+			allowed, warnings, result := doValidatePizzaRecipe(ctx, review.Request.Object.Raw, review.Request.OldObject.Raw, toppingLister)
+			review.Response = &admissionv1.AdmissionResponse{UID: review.Request.UID, Allowed: allowed, Warnings: warnings, Result: result}
+			review.Request = &admissionv1.AdmissionRequest{}
+			responseObj = review
+		case admissionv1beta1.SchemeGroupVersion.WithKind("AdmissionReview"):
+			review, ok := obj.(*admissionv1beta1.AdmissionReview)
+			if !ok {
+				msg := fmt.Sprintf("Expected a v1beta1.AdmissionReview but got: %T", obj)
+				logger.Error(nil, "Rejecting malformed request", "reason", msg)
+				http.Error(w, msg, http.StatusBadRequest)
+				return
+			}
+			if review.Request == nil {
+				msg := "unexpected nil request"
+				logger.Error(nil, "Rejecting malformed request", "reason", msg)
+				http.Error(w, msg, http.StatusBadRequest)
+				return
+			}
+			ctx := withRequestLogger(req.Context(), review.Request.UID, review.Request.Namespace, review.Request.Name, review.Request.Operation)
+			allowed, warnings, result := doValidatePizzaRecipe(ctx, review.Request.Object.Raw, review.Request.OldObject.Raw, toppingLister)
+			review.Response = &admissionv1beta1.AdmissionResponse{UID: review.Request.UID, Allowed: allowed, Warnings: warnings, Result: result}
+			review.Request = &admissionv1beta1.AdmissionRequest{}
+			responseObj = review
+		default:
+			msg := fmt.Sprintf("unexpected GroupVersionKind: %v", gvk)
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		webhook.SendResponse(w, req, responseObj)
+	}
+}
+
+// doValidatePizzaRecipe validates the raw recipe of a review. oldRaw is empty
+// on create.
+func doValidatePizzaRecipe(ctx context.Context, raw, oldRaw []byte, toppingLister restaurantv1alpha1.ToppingLister) (bool, []string, *metav1.Status) {
+	recipe, err := decodePizzaRecipe(ctx, raw)
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to decode pizza recipe")
+		return false, nil, &metav1.Status{Message: err.Error(), Status: metav1.StatusFailure}
+	}
+	var oldRecipe *v1beta2.PizzaRecipe
+	if len(oldRaw) > 0 {
+		if oldRecipe, err = decodePizzaRecipe(ctx, oldRaw); err != nil {
+			klog.FromContext(ctx).Error(err, "Failed to decode old pizza recipe")
+			return false, nil, &metav1.Status{Message: err.Error(), Status: metav1.StatusFailure}
+		}
+	}
+	warnings, allErrs := ValidatePizzaRecipe(ctx, recipe, oldRecipe, toppingLister, time.Now())
+	if err := allErrs.ToAggregate(); err != nil {
+		klog.FromContext(ctx).V(2).Info("Rejecting invalid pizza recipe", "err", err)
+		return false, nil, &metav1.Status{Message: err.Error(), Status: metav1.StatusFailure}
+	}
+	return true, warnings, &metav1.Status{Message: "pizza recipe is valid", Status: metav1.StatusSuccess}
+}
+
+func decodePizzaRecipe(ctx context.Context, raw []byte) (*v1beta2.PizzaRecipe, error) {
+	obj, _, err := webhook.Decode(ctx, webhook.Codecs.UniversalDeserializer(), raw)
+	if err != nil {
+		return nil, err
+	}
+	recipe, ok := obj.(*v1beta2.PizzaRecipe)
+	if !ok {
+		return nil, fmt.Errorf("unexpected pizza recipe type: %T", obj)
+	}
+	return recipe, nil
+}
+
+// ValidatePizzaRecipe checks that all toppings of a PizzaRecipe exist and that
+// none of them is retired at now, unless the old recipe had it already.
+// oldRecipe is nil on create. Like for pizzas, it warns about deprecated
+// toppings and retired toppings the recipe keeps.
+func ValidatePizzaRecipe(ctx context.Context, recipe, oldRecipe *v1beta2.PizzaRecipe, toppingLister restaurantv1alpha1.ToppingLister, now time.Time) ([]string, field.ErrorList) {
+	had := map[string]bool{}
+	if oldRecipe != nil {
+		for _, topping := range oldRecipe.Spec.Toppings {
+			had[topping.Name] = true
+		}
+	}
+
+	var warnings []string
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "toppings")
+	for i, t := range recipe.Spec.Toppings {
+		namePath := fldPath.Index(i).Child("name")
+		topping, err := getTopping(ctx, toppingLister, t.Name)
+		if errors.IsNotFound(err) {
+			allErrs = append(allErrs, field.NotFound(namePath, t.Name))
+			continue
+		} else if err != nil {
+			allErrs = append(allErrs, field.InternalError(namePath, fmt.Errorf("failed to lookup topping %q: %v", t.Name, err)))
+			continue
+		}
+		switch topping.EffectivePhase(now) {
+		case v1alpha1.ToppingDeprecated:
+			warnings = append(warnings, fmt.Sprintf("topping %q is deprecated", t.Name))
+		case v1alpha1.ToppingRetired:
+			if !had[t.Name] {
+				allErrs = append(allErrs, field.Forbidden(namePath, fmt.Sprintf("topping %q is retired", t.Name)))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("topping %q is retired, it cannot be added to other recipes", t.Name))
+			}
+		}
+	}
+	return warnings, allErrs
+}
@@ -0,0 +1,47 @@
+package admission_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	webhooktesting "github.com/zeroisme/pizza-crd/pkg/webhook/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestValidatePizzaRecipe(t *testing.T) {
+	sunset := metav1.NewTime(time.Now().Add(-time.Hour))
+	s := webhooktesting.NewServer(t, []runtime.Object{
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "tomato"}, Spec: v1alpha1.ToppingSpec{Cost: 0.5, Vegan: true}},
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "anchovies"}, Spec: v1alpha1.ToppingSpec{Cost: 1.5, SunsetTime: &sunset}},
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "pineapple"}, Spec: v1alpha1.ToppingSpec{Cost: 1, Phase: v1alpha1.ToppingDeprecated}},
+	})
+	recipe := func(toppings ...string) *v1beta2.PizzaRecipe {
+		r := &v1beta2.PizzaRecipe{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "house"}}
+		for _, name := range toppings {
+			r.Spec.Toppings = append(r.Spec.Toppings, v1beta2.PizzaTopping{Name: name, Quantity: 1})
+		}
+		return r
+	}
+
+	for _, version := range webhooktesting.AdmissionReviewVersions {
+		t.Run(version, func(t *testing.T) {
+			review := webhooktesting.NewAdmissionReview(version, recipe("tomato")).Build(t)
+			s.ValidateRecipe(t, review).ExpectAllowed(t)
+
+			review = webhooktesting.NewAdmissionReview(version, recipe("tomato", "salami")).Build(t)
+			s.ValidateRecipe(t, review).ExpectDenied(t, `spec.toppings[1].name: Not found: "salami"`)
+
+			review = webhooktesting.NewAdmissionReview(version, recipe("tomato", "anchovies")).Build(t)
+			s.ValidateRecipe(t, review).ExpectDenied(t, `topping "anchovies" is retired`)
+
+			review = webhooktesting.NewAdmissionReview(version, recipe("tomato", "anchovies")).WithOldObject(recipe("anchovies")).Build(t)
+			s.ValidateRecipe(t, review).ExpectAllowed(t).ExpectWarning(t, `topping "anchovies" is retired`)
+
+			review = webhooktesting.NewAdmissionReview(version, recipe("pineapple")).Build(t)
+			s.ValidateRecipe(t, review).ExpectAllowed(t).ExpectWarning(t, `topping "pineapple" is deprecated`)
+		})
+	}
+}
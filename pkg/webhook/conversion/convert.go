@@ -1,69 +1,382 @@
 package conversion
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
 	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/klog/v2"
 )
 
-func convert(in runtime.Object, apiVersion string) (runtime.Object, error) {
+// Convert converts a Pizza to the given apiVersion. It is the conversion the
+// webhook applies, so offline tools must use it as well.
+//
+// Pizzas are converted through v1beta2, which can represent every field. The
+// size of v1beta2 Pizzas is kept in the v1alpha1.SizeAnnotation annotation in
+// the older versions. Their sections are flattened into spec.toppings there
+// and kept in the v1alpha1.SectionsAnnotation annotation, so that they are
+// split out again when converting back.
+func Convert(in runtime.Object, apiVersion string) (runtime.Object, error) {
+	var hub *v1beta2.Pizza
 	switch in := in.(type) {
 	case *v1alpha1.Pizza:
-		if apiVersion != v1beta1.SchemeGroupVersion.String() {
-			return nil, fmt.Errorf("cannot convet %s to %s", v1alpha1.SchemeGroupVersion, apiVersion)
+		hub = v1alpha1ToV1beta2(in)
+	case *v1beta1.Pizza:
+		hub = v1beta1ToV1beta2(in)
+	case *v1beta2.Pizza:
+		hub = in.DeepCopy()
+	default:
+		return nil, fmt.Errorf("unknown type %T", in)
+	}
+
+	var out runtime.Object
+	switch apiVersion {
+	case v1alpha1.SchemeGroupVersion.String():
+		out = v1beta2ToV1alpha1(hub)
+	case v1beta1.SchemeGroupVersion.String():
+		out = v1beta2ToV1beta1(hub)
+	case v1beta2.SchemeGroupVersion.String():
+		out = hub
+	default:
+		return nil, fmt.Errorf("cannot convert %s to %s", in.GetObjectKind().GroupVersionKind().GroupVersion(), apiVersion)
+	}
+	out.GetObjectKind().SetGroupVersionKind(out.GetObjectKind().GroupVersionKind().GroupVersion().WithKind("Pizza"))
+	return out, nil
+}
+
+func v1alpha1ToV1beta2(in *v1alpha1.Pizza) *v1beta2.Pizza {
+	in = in.DeepCopy()
+	out := &v1beta2.Pizza{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: in.ObjectMeta,
+		Status: v1beta2.PizzaStatus{
+			Cost:       in.Status.Cost,
+			Conditions: in.Status.Conditions,
+		},
+	}
+	out.TypeMeta.APIVersion = v1beta2.SchemeGroupVersion.String()
+	out.Spec.Size = v1beta2.PizzaSize(popAnnotation(&out.ObjectMeta.Annotations, v1alpha1.SizeAnnotation))
+	for _, claim := range in.Spec.DietaryClaims {
+		out.Spec.DietaryClaims = append(out.Spec.DietaryClaims, v1beta2.DietaryClaim(claim))
+	}
+	for _, allergen := range in.Status.Allergens {
+		out.Status.Allergens = append(out.Status.Allergens, v1beta2.Allergen(allergen))
+	}
+	if n := in.Status.Nutrition; n != nil {
+		out.Status.Nutrition = &v1beta2.Nutrition{Calories: n.Calories, Protein: n.Protein, Fat: n.Fat, Salt: n.Salt}
+	}
+	if b := in.Status.CostBreakdown; b != nil {
+		out.Status.CostBreakdown = &v1beta2.CostBreakdown{Base: b.Base, Final: b.Final}
+		for _, d := range b.Discounts {
+			out.Status.CostBreakdown.Discounts = append(out.Status.CostBreakdown.Discounts, v1beta2.AppliedDiscount{Promotion: d.Promotion, Amount: d.Amount})
 		}
-		klog.V(2).Infof("Converting %s/%s from %s to %s", in.Namespace, in.Name, v1alpha1.SchemeGroupVersion, apiVersion)
-
-		out := &v1beta1.Pizza{
-			TypeMeta:   in.TypeMeta,
-			ObjectMeta: in.ObjectMeta,
-			Status: v1beta1.PizzaStatus{
-				Cost: in.Status.Cost,
-			},
+	}
+
+	if in.Spec.RecipeRef != nil {
+		out.Spec.RecipeRef = &v1beta2.PizzaRecipeReference{Name: in.Spec.RecipeRef.Name}
+	}
+	out.Spec.Additions = toppingsFromNames(in.Spec.Additions)
+	out.Spec.Removals = in.Spec.Removals
+	if r := in.Status.Recipe; r != nil {
+		out.Status.Recipe = &v1beta2.RecipeStatus{
+			Toppings:           toppingsFromNames(r.Toppings),
+			RecipeGeneration:   r.RecipeGeneration,
+			ObservedGeneration: r.ObservedGeneration,
 		}
-		out.TypeMeta.APIVersion = apiVersion
+	}
 
-		idx := map[string]int{}
-		for _, top := range in.Spec.Toppings {
-			if i, duplicate := idx[top]; duplicate {
-				out.Spec.Toppings[i].Quantity++
-				continue
-			}
-			idx[top] = len(out.Spec.Toppings)
-			out.Spec.Toppings = append(out.Spec.Toppings, v1beta1.PizzaTopping{
-				Name:     top,
-				Quantity: 1,
-			})
+	out.Spec.Toppings = toppingsFromNames(in.Spec.Toppings)
+	splitSections(out)
+	return out
+}
+
+func v1beta2ToV1alpha1(in *v1beta2.Pizza) *v1alpha1.Pizza {
+	out := &v1alpha1.Pizza{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: in.ObjectMeta,
+		Status: v1alpha1.PizzaStatus{
+			Cost:       in.Status.Cost,
+			Conditions: in.Status.Conditions,
+		},
+	}
+	out.TypeMeta.APIVersion = v1alpha1.SchemeGroupVersion.String()
+	pushAnnotation(&out.ObjectMeta.Annotations, v1alpha1.SizeAnnotation, string(in.Spec.Size))
+	pushSectionsAnnotation(&out.ObjectMeta.Annotations, in.Spec.Sections)
+	for _, claim := range in.Spec.DietaryClaims {
+		out.Spec.DietaryClaims = append(out.Spec.DietaryClaims, v1alpha1.DietaryClaim(claim))
+	}
+	for _, allergen := range in.Status.Allergens {
+		out.Status.Allergens = append(out.Status.Allergens, v1alpha1.Allergen(allergen))
+	}
+	if n := in.Status.Nutrition; n != nil {
+		out.Status.Nutrition = &v1alpha1.Nutrition{Calories: n.Calories, Protein: n.Protein, Fat: n.Fat, Salt: n.Salt}
+	}
+	if b := in.Status.CostBreakdown; b != nil {
+		out.Status.CostBreakdown = &v1alpha1.CostBreakdown{Base: b.Base, Final: b.Final}
+		for _, d := range b.Discounts {
+			out.Status.CostBreakdown.Discounts = append(out.Status.CostBreakdown.Discounts, v1alpha1.AppliedDiscount{Promotion: d.Promotion, Amount: d.Amount})
 		}
-		return out, nil
+	}
 
-	case *v1beta1.Pizza:
-		if apiVersion != v1alpha1.SchemeGroupVersion.String() {
-			return nil, fmt.Errorf("cannot convert %s to %s", v1beta1.SchemeGroupVersion, apiVersion)
+	if in.Spec.RecipeRef != nil {
+		out.Spec.RecipeRef = &v1alpha1.PizzaRecipeReference{Name: in.Spec.RecipeRef.Name}
+	}
+	out.Spec.Additions = namesFromToppings(in.Spec.Additions)
+	out.Spec.Removals = in.Spec.Removals
+	if r := in.Status.Recipe; r != nil {
+		out.Status.Recipe = &v1alpha1.RecipeStatus{
+			Toppings:           namesFromToppings(r.Toppings),
+			RecipeGeneration:   r.RecipeGeneration,
+			ObservedGeneration: r.ObservedGeneration,
+		}
+	}
+
+	out.Spec.Toppings = namesFromToppings(flatToppings(in))
+	return out
+}
+
+func v1beta1ToV1beta2(in *v1beta1.Pizza) *v1beta2.Pizza {
+	in = in.DeepCopy()
+	out := &v1beta2.Pizza{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: in.ObjectMeta,
+		Status: v1beta2.PizzaStatus{
+			Cost:       in.Status.Cost,
+			Conditions: in.Status.Conditions,
+		},
+	}
+	out.TypeMeta.APIVersion = v1beta2.SchemeGroupVersion.String()
+	out.Spec.Size = v1beta2.PizzaSize(popAnnotation(&out.ObjectMeta.Annotations, v1alpha1.SizeAnnotation))
+	for _, claim := range in.Spec.DietaryClaims {
+		out.Spec.DietaryClaims = append(out.Spec.DietaryClaims, v1beta2.DietaryClaim(claim))
+	}
+	for _, allergen := range in.Status.Allergens {
+		out.Status.Allergens = append(out.Status.Allergens, v1beta2.Allergen(allergen))
+	}
+	if n := in.Status.Nutrition; n != nil {
+		out.Status.Nutrition = &v1beta2.Nutrition{Calories: n.Calories, Protein: n.Protein, Fat: n.Fat, Salt: n.Salt}
+	}
+	if b := in.Status.CostBreakdown; b != nil {
+		out.Status.CostBreakdown = &v1beta2.CostBreakdown{Base: b.Base, Final: b.Final}
+		for _, d := range b.Discounts {
+			out.Status.CostBreakdown.Discounts = append(out.Status.CostBreakdown.Discounts, v1beta2.AppliedDiscount{Promotion: d.Promotion, Amount: d.Amount})
+		}
+	}
+	if in.Spec.RecipeRef != nil {
+		out.Spec.RecipeRef = &v1beta2.PizzaRecipeReference{Name: in.Spec.RecipeRef.Name}
+	}
+	out.Spec.Additions = fromV1beta1Toppings(in.Spec.Additions)
+	out.Spec.Removals = in.Spec.Removals
+	if r := in.Status.Recipe; r != nil {
+		out.Status.Recipe = &v1beta2.RecipeStatus{
+			Toppings:           fromV1beta1Toppings(r.Toppings),
+			RecipeGeneration:   r.RecipeGeneration,
+			ObservedGeneration: r.ObservedGeneration,
+		}
+	}
+	out.Spec.Toppings = fromV1beta1Toppings(in.Spec.Toppings)
+	splitSections(out)
+	return out
+}
+
+func v1beta2ToV1beta1(in *v1beta2.Pizza) *v1beta1.Pizza {
+	out := &v1beta1.Pizza{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: in.ObjectMeta,
+		Status: v1beta1.PizzaStatus{
+			Cost:       in.Status.Cost,
+			Conditions: in.Status.Conditions,
+		},
+	}
+	out.TypeMeta.APIVersion = v1beta1.SchemeGroupVersion.String()
+	pushAnnotation(&out.ObjectMeta.Annotations, v1alpha1.SizeAnnotation, string(in.Spec.Size))
+	pushSectionsAnnotation(&out.ObjectMeta.Annotations, in.Spec.Sections)
+	for _, claim := range in.Spec.DietaryClaims {
+		out.Spec.DietaryClaims = append(out.Spec.DietaryClaims, v1beta1.DietaryClaim(claim))
+	}
+	for _, allergen := range in.Status.Allergens {
+		out.Status.Allergens = append(out.Status.Allergens, v1beta1.Allergen(allergen))
+	}
+	if n := in.Status.Nutrition; n != nil {
+		out.Status.Nutrition = &v1beta1.Nutrition{Calories: n.Calories, Protein: n.Protein, Fat: n.Fat, Salt: n.Salt}
+	}
+	if b := in.Status.CostBreakdown; b != nil {
+		out.Status.CostBreakdown = &v1beta1.CostBreakdown{Base: b.Base, Final: b.Final}
+		for _, d := range b.Discounts {
+			out.Status.CostBreakdown.Discounts = append(out.Status.CostBreakdown.Discounts, v1beta1.AppliedDiscount{Promotion: d.Promotion, Amount: d.Amount})
+		}
+	}
+	if in.Spec.RecipeRef != nil {
+		out.Spec.RecipeRef = &v1beta1.PizzaRecipeReference{Name: in.Spec.RecipeRef.Name}
+	}
+	out.Spec.Additions = toV1beta1Toppings(in.Spec.Additions)
+	out.Spec.Removals = in.Spec.Removals
+	if r := in.Status.Recipe; r != nil {
+		out.Status.Recipe = &v1beta1.RecipeStatus{
+			Toppings:           toV1beta1Toppings(r.Toppings),
+			RecipeGeneration:   r.RecipeGeneration,
+			ObservedGeneration: r.ObservedGeneration,
+		}
+	}
+	out.Spec.Toppings = toV1beta1Toppings(flatToppings(in))
+	return out
+}
+
+// toppingsFromNames turns the topping names of v1alpha1 into toppings with
+// the number of duplicates as quantity.
+func toppingsFromNames(names []string) []v1beta2.PizzaTopping {
+	var toppings []v1beta2.PizzaTopping
+	idx := map[string]int{}
+	for _, name := range names {
+		if i, duplicate := idx[name]; duplicate {
+			toppings[i].Quantity++
+			continue
+		}
+		idx[name] = len(toppings)
+		toppings = append(toppings, v1beta2.PizzaTopping{
+			Name:     name,
+			Quantity: 1,
+		})
+	}
+	return toppings
+}
+
+// namesFromToppings repeats the name of each topping by its quantity.
+func namesFromToppings(toppings []v1beta2.PizzaTopping) []string {
+	var names []string
+	for _, t := range toppings {
+		for j := 0; j < t.Quantity; j++ {
+			names = append(names, t.Name)
 		}
-		klog.V(2).Infof("Converting %s/%s from %s to %s", in.Namespace, in.Name, v1alpha1.SchemeGroupVersion, apiVersion)
-
-		out := &v1alpha1.Pizza{
-			TypeMeta:   in.TypeMeta,
-			ObjectMeta: in.ObjectMeta,
-			Status: v1alpha1.PizzaStatus{
-				Cost: in.Status.Cost,
-			},
+	}
+	return names
+}
+
+func fromV1beta1Toppings(in []v1beta1.PizzaTopping) []v1beta2.PizzaTopping {
+	var out []v1beta2.PizzaTopping
+	for _, t := range in {
+		out = append(out, v1beta2.PizzaTopping{Name: t.Name, Quantity: t.Quantity})
+	}
+	return out
+}
+
+func toV1beta1Toppings(in []v1beta2.PizzaTopping) []v1beta1.PizzaTopping {
+	var out []v1beta1.PizzaTopping
+	for _, t := range in {
+		out = append(out, v1beta1.PizzaTopping{Name: t.Name, Quantity: t.Quantity})
+	}
+	return out
+}
+
+// SectionsOf decodes the v1alpha1.SectionsAnnotation annotation of a Pizza in
+// the versions without spec.sections. It returns nil if it is not set.
+func SectionsOf(annotations map[string]string) ([]v1beta2.PizzaSection, error) {
+	value, ok := annotations[v1alpha1.SectionsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var sections []v1beta2.PizzaSection
+	if err := json.Unmarshal([]byte(value), &sections); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %v", v1alpha1.SectionsAnnotation, err)
+	}
+	return sections, nil
+}
+
+// flatToppings returns the toppings of the whole pizza followed by the
+// toppings of its sections. Section toppings are added to the first topping
+// of the same name if there is one.
+func flatToppings(pizza *v1beta2.Pizza) []v1beta2.PizzaTopping {
+	toppings := append([]v1beta2.PizzaTopping(nil), pizza.Spec.Toppings...)
+	for _, section := range pizza.Spec.Sections {
+	nextTopping:
+		for _, t := range section.Toppings {
+			for i := range toppings {
+				if toppings[i].Name == t.Name {
+					toppings[i].Quantity += t.Quantity
+					continue nextTopping
+				}
+			}
+			toppings = append(toppings, t)
 		}
-		out.TypeMeta.APIVersion = apiVersion
+	}
+	return toppings
+}
 
-		for i := range in.Spec.Toppings {
-			for j := 0; j < in.Spec.Toppings[i].Quantity; j++ {
-				out.Spec.Toppings = append(out.Spec.Toppings, in.Spec.Toppings[i].Name)
+// splitSections moves the toppings of the sections in the sections annotation
+// out of spec.toppings into spec.sections. The pizza is left unchanged and
+// the annotation is kept if it is invalid or if spec.toppings does not
+// contain the toppings of the sections anymore, e.g. because an older client
+// removed them.
+func splitSections(pizza *v1beta2.Pizza) {
+	sections, err := SectionsOf(pizza.Annotations)
+	if err != nil || len(sections) == 0 {
+		return
+	}
+	whole := append([]v1beta2.PizzaTopping(nil), pizza.Spec.Toppings...)
+	for _, section := range sections {
+		for _, t := range section.Toppings {
+			remaining := t.Quantity
+			for i := len(whole) - 1; i >= 0 && remaining > 0; i-- {
+				if whole[i].Name != t.Name {
+					continue
+				}
+				n := whole[i].Quantity
+				if n > remaining {
+					n = remaining
+				}
+				whole[i].Quantity -= n
+				remaining -= n
+			}
+			if remaining > 0 {
+				return
 			}
 		}
+	}
 
-		return out, nil
-	default:
+	pizza.Spec.Toppings = make([]v1beta2.PizzaTopping, 0, len(whole))
+	for _, t := range whole {
+		if t.Quantity > 0 {
+			pizza.Spec.Toppings = append(pizza.Spec.Toppings, t)
+		}
+	}
+	pizza.Spec.Sections = sections
+	popAnnotation(&pizza.Annotations, v1alpha1.SectionsAnnotation)
+}
+
+// popAnnotation removes an annotation and returns its value. The annotations
+// must not be shared with another object.
+func popAnnotation(annotations *map[string]string, key string) string {
+	value := (*annotations)[key]
+	delete(*annotations, key)
+	if len(*annotations) == 0 {
+		*annotations = nil
+	}
+	return value
+}
+
+// pushAnnotation sets an annotation unless value is empty. The annotations
+// are copied, so that the source object is not changed.
+func pushAnnotation(annotations *map[string]string, key, value string) {
+	if len(value) == 0 {
+		return
+	}
+	copied := make(map[string]string, len(*annotations)+1)
+	for k, v := range *annotations {
+		copied[k] = v
+	}
+	copied[key] = value
+	*annotations = copied
+}
+
+// pushSectionsAnnotation sets the sections annotation unless there are no
+// sections.
+func pushSectionsAnnotation(annotations *map[string]string, sections []v1beta2.PizzaSection) {
+	if len(sections) == 0 {
+		return
 	}
-	klog.V(2).Infof("Unknown type: %T", in)
-	return nil, fmt.Errorf("unknown type %T", in)
+	// sections were decoded from JSON, so they always encode.
+	value, _ := json.Marshal(sections)
+	pushAnnotation(annotations, v1alpha1.SectionsAnnotation, string(value))
 }
@@ -0,0 +1,244 @@
+package conversion
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const halvesAnnotation = `[{"name":"left","coverage":0.5,"toppings":[{"name":"salami","quantity":1}]},{"name":"right","coverage":0.5,"toppings":[{"name":"ham","quantity":2}]}]`
+
+func TestConvertRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      runtime.Object
+		through []string
+	}{
+		{
+			name: "v1beta2 with size and sections",
+			in: &v1beta2.Pizza{
+				TypeMeta:   metav1.TypeMeta{APIVersion: v1beta2.SchemeGroupVersion.String(), Kind: "Pizza"},
+				ObjectMeta: metav1.ObjectMeta{Name: "halves"},
+				Spec: v1beta2.PizzaSpec{
+					Toppings: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}},
+					Size:     v1beta2.PizzaSizeLarge,
+					Sections: []v1beta2.PizzaSection{
+						{Name: "left", Coverage: 0.5, Toppings: []v1beta2.PizzaTopping{{Name: "salami", Quantity: 1}}},
+						{Name: "right", Coverage: 0.5, Toppings: []v1beta2.PizzaTopping{{Name: "ham", Quantity: 2}}},
+					},
+				},
+			},
+			through: []string{v1alpha1.SchemeGroupVersion.String(), v1beta1.SchemeGroupVersion.String()},
+		},
+		{
+			name: "v1beta2 with toppings on the whole pizza and a section",
+			in: &v1beta2.Pizza{
+				TypeMeta:   metav1.TypeMeta{APIVersion: v1beta2.SchemeGroupVersion.String(), Kind: "Pizza"},
+				ObjectMeta: metav1.ObjectMeta{Name: "shared", Annotations: map[string]string{"note": "extra salami"}},
+				Spec: v1beta2.PizzaSpec{
+					Toppings: []v1beta2.PizzaTopping{{Name: "salami", Quantity: 1}, {Name: "tomato", Quantity: 1}},
+					Sections: []v1beta2.PizzaSection{
+						{Name: "left", Coverage: 0.5, Toppings: []v1beta2.PizzaTopping{{Name: "salami", Quantity: 2}}},
+						{Name: "right", Coverage: 0.5, Toppings: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}}},
+					},
+				},
+			},
+			through: []string{v1alpha1.SchemeGroupVersion.String(), v1beta1.SchemeGroupVersion.String()},
+		},
+		{
+			name: "v1beta2 without size and sections",
+			in: &v1beta2.Pizza{
+				TypeMeta:   metav1.TypeMeta{APIVersion: v1beta2.SchemeGroupVersion.String(), Kind: "Pizza"},
+				ObjectMeta: metav1.ObjectMeta{Name: "plain"},
+				Spec: v1beta2.PizzaSpec{
+					Toppings: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 2}},
+				},
+			},
+			through: []string{v1alpha1.SchemeGroupVersion.String(), v1beta1.SchemeGroupVersion.String()},
+		},
+		{
+			name: "v1beta1 with size and sections annotations",
+			in: &v1beta1.Pizza{
+				TypeMeta: metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: "Pizza"},
+				ObjectMeta: metav1.ObjectMeta{Name: "halves", Annotations: map[string]string{
+					v1alpha1.SizeAnnotation:     "family",
+					v1alpha1.SectionsAnnotation: halvesAnnotation,
+				}},
+				Spec: v1beta1.PizzaSpec{
+					Toppings: []v1beta1.PizzaTopping{{Name: "tomato", Quantity: 1}, {Name: "salami", Quantity: 1}, {Name: "ham", Quantity: 2}},
+				},
+			},
+			through: []string{v1beta2.SchemeGroupVersion.String(), v1alpha1.SchemeGroupVersion.String()},
+		},
+	}
+	for _, test := range tests {
+		for _, version := range test.through {
+			t.Run(test.name+"/"+version, func(t *testing.T) {
+				converted, err := Convert(test.in, version)
+				if err != nil {
+					t.Fatalf("failed to convert to %s: %v", version, err)
+				}
+				back, err := Convert(converted, test.in.GetObjectKind().GroupVersionKind().GroupVersion().String())
+				if err != nil {
+					t.Fatalf("failed to convert back from %s: %v", version, err)
+				}
+				if !apiequality.Semantic.DeepEqual(test.in, back) {
+					t.Errorf("round-trip through %s changed the pizza (-want +got):\n%s", version, cmp.Diff(test.in, back))
+				}
+			})
+		}
+	}
+}
+
+func TestFlatToppings(t *testing.T) {
+	tests := []struct {
+		name string
+		spec v1beta2.PizzaSpec
+		want []v1beta2.PizzaTopping
+	}{
+		{
+			name: "no sections",
+			spec: v1beta2.PizzaSpec{Toppings: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}}},
+			want: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}},
+		},
+		{
+			name: "section toppings appended",
+			spec: v1beta2.PizzaSpec{
+				Toppings: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}},
+				Sections: []v1beta2.PizzaSection{
+					{Name: "left", Coverage: 0.5, Toppings: []v1beta2.PizzaTopping{{Name: "salami", Quantity: 1}}},
+					{Name: "right", Coverage: 0.5, Toppings: []v1beta2.PizzaTopping{{Name: "ham", Quantity: 2}}},
+				},
+			},
+			want: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}, {Name: "salami", Quantity: 1}, {Name: "ham", Quantity: 2}},
+		},
+		{
+			name: "toppings shared by the whole pizza and sections",
+			spec: v1beta2.PizzaSpec{
+				Toppings: []v1beta2.PizzaTopping{{Name: "salami", Quantity: 1}, {Name: "tomato", Quantity: 1}},
+				Sections: []v1beta2.PizzaSection{
+					{Name: "left", Coverage: 0.5, Toppings: []v1beta2.PizzaTopping{{Name: "salami", Quantity: 2}, {Name: "olives", Quantity: 1}}},
+					{Name: "right", Coverage: 0.5, Toppings: []v1beta2.PizzaTopping{{Name: "olives", Quantity: 1}, {Name: "tomato", Quantity: 1}}},
+				},
+			},
+			want: []v1beta2.PizzaTopping{{Name: "salami", Quantity: 3}, {Name: "tomato", Quantity: 2}, {Name: "olives", Quantity: 2}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pizza := &v1beta2.Pizza{Spec: test.spec}
+			original := pizza.DeepCopy()
+			got := flatToppings(pizza)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("unexpected toppings (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(original, pizza); diff != "" {
+				t.Errorf("pizza changed (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSplitSections(t *testing.T) {
+	sections := []v1beta2.PizzaSection{
+		{Name: "left", Coverage: 0.5, Toppings: []v1beta2.PizzaTopping{{Name: "salami", Quantity: 1}}},
+		{Name: "right", Coverage: 0.5, Toppings: []v1beta2.PizzaTopping{{Name: "ham", Quantity: 2}}},
+	}
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		toppings    []v1beta2.PizzaTopping
+		want        v1beta2.PizzaSpec
+		// wantAnnotations are the annotations left, nil if there are none.
+		wantAnnotations map[string]string
+	}{
+		{
+			name:     "no annotation",
+			toppings: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}},
+			want:     v1beta2.PizzaSpec{Toppings: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}}},
+		},
+		{
+			name:        "sections split out",
+			annotations: map[string]string{v1alpha1.SectionsAnnotation: halvesAnnotation},
+			toppings:    []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}, {Name: "salami", Quantity: 1}, {Name: "ham", Quantity: 2}},
+			want: v1beta2.PizzaSpec{
+				Toppings: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}},
+				Sections: sections,
+			},
+		},
+		{
+			name:        "toppings shared by the whole pizza and a section",
+			annotations: map[string]string{v1alpha1.SectionsAnnotation: halvesAnnotation},
+			toppings:    []v1beta2.PizzaTopping{{Name: "salami", Quantity: 2}, {Name: "ham", Quantity: 3}},
+			want: v1beta2.PizzaSpec{
+				Toppings: []v1beta2.PizzaTopping{{Name: "salami", Quantity: 1}, {Name: "ham", Quantity: 1}},
+				Sections: sections,
+			},
+		},
+		{
+			name:        "duplicates of a section topping taken from the end",
+			annotations: map[string]string{v1alpha1.SectionsAnnotation: halvesAnnotation},
+			toppings:    []v1beta2.PizzaTopping{{Name: "ham", Quantity: 1}, {Name: "salami", Quantity: 1}, {Name: "ham", Quantity: 2}},
+			want: v1beta2.PizzaSpec{
+				Toppings: []v1beta2.PizzaTopping{{Name: "ham", Quantity: 1}},
+				Sections: sections,
+			},
+		},
+		{
+			name:        "old client removed a section topping",
+			annotations: map[string]string{v1alpha1.SectionsAnnotation: halvesAnnotation},
+			toppings:    []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}, {Name: "ham", Quantity: 2}},
+			want: v1beta2.PizzaSpec{
+				Toppings: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}, {Name: "ham", Quantity: 2}},
+			},
+			wantAnnotations: map[string]string{v1alpha1.SectionsAnnotation: halvesAnnotation},
+		},
+		{
+			name:        "old client removed one of several units of a section topping",
+			annotations: map[string]string{v1alpha1.SectionsAnnotation: halvesAnnotation},
+			toppings:    []v1beta2.PizzaTopping{{Name: "salami", Quantity: 1}, {Name: "ham", Quantity: 1}},
+			want: v1beta2.PizzaSpec{
+				Toppings: []v1beta2.PizzaTopping{{Name: "salami", Quantity: 1}, {Name: "ham", Quantity: 1}},
+			},
+			wantAnnotations: map[string]string{v1alpha1.SectionsAnnotation: halvesAnnotation},
+		},
+		{
+			name:        "old client removed the whole pizza share of a shared topping",
+			annotations: map[string]string{v1alpha1.SectionsAnnotation: halvesAnnotation},
+			toppings:    []v1beta2.PizzaTopping{{Name: "salami", Quantity: 1}, {Name: "ham", Quantity: 2}},
+			want: v1beta2.PizzaSpec{
+				Toppings: []v1beta2.PizzaTopping{},
+				Sections: sections,
+			},
+		},
+		{
+			name:            "invalid annotation",
+			annotations:     map[string]string{v1alpha1.SectionsAnnotation: "left"},
+			toppings:        []v1beta2.PizzaTopping{{Name: "salami", Quantity: 1}},
+			want:            v1beta2.PizzaSpec{Toppings: []v1beta2.PizzaTopping{{Name: "salami", Quantity: 1}}},
+			wantAnnotations: map[string]string{v1alpha1.SectionsAnnotation: "left"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pizza := &v1beta2.Pizza{
+				ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations},
+				Spec:       v1beta2.PizzaSpec{Toppings: test.toppings},
+			}
+			pizza = pizza.DeepCopy()
+			splitSections(pizza)
+			if diff := cmp.Diff(test.want, pizza.Spec); diff != "" {
+				t.Errorf("unexpected spec (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.wantAnnotations, pizza.Annotations); diff != "" {
+				t.Errorf("unexpected annotations (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
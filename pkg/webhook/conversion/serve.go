@@ -1,42 +1,46 @@
 package conversion
 
 import (
+	"context"
 	"fmt"
-	"io"
 	"net/http"
 
 	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/component-base/tracing"
 	"k8s.io/klog/v2"
 )
 
 func Serve(w http.ResponseWriter, req *http.Request) {
-	var body []byte
-	if req.Body != nil {
-		if data, err := io.ReadAll(req.Body); err == nil {
-			body = data
-		}
+	body, err := webhook.ReadBody(req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read body: %v", err), webhook.ReadBodyErrorCode(err))
+		return
 	}
+	logger := klog.FromContext(req.Context())
 
 	contentType := req.Header.Get("Content-Type")
 	serializer := webhook.GetInputSerializer(contentType)
 
 	if serializer == nil {
 		msg := fmt.Sprintf("invalid Content-Type header `%s`", contentType)
-		klog.Errorf(msg)
+		logger.Error(nil, "Rejecting malformed request", "reason", msg)
 		http.Error(w, msg, http.StatusBadRequest)
 		return
 	}
 
-	klog.V(2).Infof("handling request: %v", body)
-	obj, gvk, err := serializer.Decode(body, nil, nil)
+	webhook.LogBody(logger, "Handling request", body)
+	obj, gvk, err := webhook.Decode(req.Context(), serializer, body)
 
 	if err != nil {
-		msg := fmt.Sprintf("failed to deserialize body (%v) with error %v", string(body), err)
-		klog.Error(err)
+		msg := fmt.Sprintf("failed to deserialize body of %d bytes: %v", len(body), err)
+		logger.Error(err, "Failed to deserialize request body", "size", len(body))
 		http.Error(w, msg, http.StatusBadRequest)
 		return
 	}
@@ -46,13 +50,13 @@ func Serve(w http.ResponseWriter, req *http.Request) {
 		convertReview, ok := obj.(*apiextensionsv1beta1.ConversionReview)
 		if !ok {
 			msg := fmt.Sprintf("Expected a v1beta1.ConversionReview but got: %T", obj)
-			klog.Errorf(msg)
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
 			http.Error(w, msg, http.StatusBadRequest)
 			return
 		}
-		convertReview.Response = doConvertionV1beta1(convertReview)
+		ctx, _ := webhook.WithReviewLogger(req.Context(), "uid", convertReview.Request.UID, "desiredAPIVersion", convertReview.Request.DesiredAPIVersion)
+		convertReview.Response = doConvertionV1beta1(ctx, convertReview)
 
-		klog.V(2).Info(fmt.Sprintf("sending response: %v", convertReview.Response))
 		// reset request
 		convertReview.Request = &apiextensionsv1beta1.ConversionRequest{}
 		responseObj = convertReview
@@ -60,19 +64,19 @@ func Serve(w http.ResponseWriter, req *http.Request) {
 		convertReview, ok := obj.(*apiextensionsv1.ConversionReview)
 		if !ok {
 			msg := fmt.Sprintf("Expected v1.ConversionReview but got: %T", obj)
-			klog.Errorf(msg)
+			logger.Error(nil, "Rejecting malformed request", "reason", msg)
 			http.Error(w, msg, http.StatusBadRequest)
 			return
 		}
-		convertReview.Response = doConvertionV1(convertReview)
-		klog.V(2).Info(fmt.Sprintf("sending response: %v", convertReview.Response))
+		ctx, _ := webhook.WithReviewLogger(req.Context(), "uid", convertReview.Request.UID, "desiredAPIVersion", convertReview.Request.DesiredAPIVersion)
+		convertReview.Response = doConvertionV1(ctx, convertReview)
 
 		// reset the request
 		convertReview.Request = &apiextensionsv1.ConversionRequest{}
 		responseObj = convertReview
 	default:
 		msg := fmt.Sprintf("Unsupported group version kind: %v", gvk)
-		klog.Error(err)
+		logger.Error(nil, "Rejecting malformed request", "reason", msg)
 		http.Error(w, msg, http.StatusBadRequest)
 		return
 	}
@@ -80,7 +84,7 @@ func Serve(w http.ResponseWriter, req *http.Request) {
 	webhook.SendResponse(w, req, responseObj)
 }
 
-func doConvertionV1beta1(review *apiextensionsv1beta1.ConversionReview) *apiextensionsv1beta1.ConversionResponse {
+func doConvertionV1beta1(ctx context.Context, review *apiextensionsv1beta1.ConversionReview) *apiextensionsv1beta1.ConversionResponse {
 	resp := &apiextensionsv1beta1.ConversionResponse{
 		Result: metav1.Status{
 			Status: metav1.StatusSuccess,
@@ -88,8 +92,9 @@ func doConvertionV1beta1(review *apiextensionsv1beta1.ConversionReview) *apiexte
 		UID: review.Request.UID,
 	}
 
-	converted, err := convert2Desired(review.Request.Objects, review.Request.DesiredAPIVersion)
+	converted, err := convert2Desired(ctx, review.Request.Objects, review.Request.DesiredAPIVersion)
 	if err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to convert objects")
 		resp.Result = metav1.Status{
 			Message: err.Error(),
 			Status:  metav1.StatusFailure,
@@ -100,7 +105,7 @@ func doConvertionV1beta1(review *apiextensionsv1beta1.ConversionReview) *apiexte
 	return resp
 }
 
-func doConvertionV1(review *apiextensionsv1.ConversionReview) *apiextensionsv1.ConversionResponse {
+func doConvertionV1(ctx context.Context, review *apiextensionsv1.ConversionReview) *apiextensionsv1.ConversionResponse {
 	resp := &apiextensionsv1.ConversionResponse{
 		Result: metav1.Status{
 			Status: metav1.StatusSuccess,
@@ -108,8 +113,9 @@ func doConvertionV1(review *apiextensionsv1.ConversionReview) *apiextensionsv1.C
 		UID: review.Request.UID,
 	}
 
-	converted, err := convert2Desired(review.Request.Objects, review.Request.DesiredAPIVersion)
+	converted, err := convert2Desired(ctx, review.Request.Objects, review.Request.DesiredAPIVersion)
 	if err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to convert objects")
 		resp.Result = metav1.Status{
 			Message: err.Error(),
 			Status:  metav1.StatusFailure,
@@ -120,17 +126,13 @@ func doConvertionV1(review *apiextensionsv1.ConversionReview) *apiextensionsv1.C
 	return resp
 }
 
-func convert2Desired(rawObjects []runtime.RawExtension, desiredAPIVersion string) ([]runtime.RawExtension, error) {
+func convert2Desired(ctx context.Context, rawObjects []runtime.RawExtension, desiredAPIVersion string) ([]runtime.RawExtension, error) {
 	var objs []runtime.Object
-	var err error
 	for _, in := range rawObjects {
-		if in.Object == nil {
-			in.Object, _, err = webhook.Codecs.UniversalDeserializer().Decode(in.Raw, nil, nil)
-		}
-		if err != nil {
-			return nil, err
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("converted %d of %d objects: %v", len(objs), len(rawObjects), err)
 		}
-		obj, err := convert(in.Object, desiredAPIVersion)
+		obj, err := convertObject(ctx, in, desiredAPIVersion)
 		if err != nil {
 			return nil, err
 		}
@@ -145,3 +147,33 @@ func convert2Desired(rawObjects []runtime.RawExtension, desiredAPIVersion string
 
 	return results, nil
 }
+
+// convertObject decodes and converts one object inside a tracing span.
+func convertObject(ctx context.Context, in runtime.RawExtension, desiredAPIVersion string) (runtime.Object, error) {
+	ctx, span := tracing.Start(ctx, "Convert object", attribute.String("to", desiredAPIVersion))
+	defer span.End(webhook.TraceThreshold)
+
+	var err error
+	if in.Object == nil {
+		in.Object, _, err = webhook.Decode(ctx, webhook.Codecs.UniversalDeserializer(), in.Raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+	from := in.Object.GetObjectKind().GroupVersionKind().GroupVersion()
+	accessor, err := meta.Accessor(in.Object)
+	if err != nil {
+		return nil, err
+	}
+	oteltrace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("namespace", accessor.GetNamespace()),
+		attribute.String("name", accessor.GetName()),
+		attribute.String("from", from.String()),
+	)
+	obj, err := Convert(in.Object, desiredAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	klog.FromContext(ctx).V(2).Info("Converted object", "object", klog.KObj(accessor), "from", from, "to", desiredAPIVersion)
+	return obj, nil
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// WithMaxRequestBodyBytes limits the size of request bodies. Reading beyond
+// limit fails with an *http.MaxBytesError, see ReadBody.
+func WithMaxRequestBodyBytes(handler http.Handler, limit int64) http.Handler {
+	if limit <= 0 {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.ContentLength > limit {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if req.Body != nil {
+			req.Body = http.MaxBytesReader(w, req.Body, limit)
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// WithRequestDeadline puts a deadline on the request context. The API server
+// passes its own webhook timeout as "timeout" query parameter; it is honored
+// as long as it is shorter than maxTimeout.
+func WithRequestDeadline(handler http.Handler, maxTimeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		timeout := maxTimeout
+		if s := req.URL.Query().Get("timeout"); len(s) > 0 {
+			if d, err := time.ParseDuration(s); err != nil {
+				klog.V(2).Infof("ignoring invalid timeout %q: %v", s, err)
+			} else if d > 0 && d < timeout {
+				timeout = d
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		handler.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// ReadBody reads the whole request body. It fails if the body exceeds the
+// limit set by WithMaxRequestBodyBytes.
+func ReadBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	return io.ReadAll(req.Body)
+}
+
+// ReadBodyErrorCode returns the HTTP status code for an error returned by ReadBody.
+func ReadBodyErrorCode(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
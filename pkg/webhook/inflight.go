@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// InFlight counts the requests currently being served.
+type InFlight struct {
+	count atomic.Int64
+}
+
+// WithTracking counts requests served by handler.
+func (f *InFlight) WithTracking(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		f.count.Add(1)
+		defer f.count.Add(-1)
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// Count returns the number of requests currently being served.
+func (f *InFlight) Count() int64 {
+	return f.count.Load()
+}
@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apiserver/pkg/server/httplog"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// bodyLogLevel is the verbosity from which request and response bodies are logged.
+const bodyLogLevel = 8
+
+const redacted = "<redacted>"
+
+// WithReviewLogger adds keysAndValues identifying a review to the contextual
+// logger and to the access log line of the request.
+func WithReviewLogger(ctx context.Context, keysAndValues ...interface{}) (context.Context, klog.Logger) {
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		httplog.AddKeyValue(ctx, fmt.Sprint(keysAndValues[i]), keysAndValues[i+1])
+	}
+	logger := klog.LoggerWithValues(klog.FromContext(ctx), keysAndValues...)
+	return klog.NewContext(ctx, logger), logger
+}
+
+// LogBody logs a redacted review body at high verbosity.
+func LogBody(logger klog.Logger, msg string, body []byte) {
+	if loggerV := logger.V(bodyLogLevel); loggerV.Enabled() {
+		loggerV.Info(msg, "body", RedactBody(body))
+	}
+}
+
+// RedactBody returns the body of an AdmissionReview or ConversionReview as
+// string with user information and managed fields removed.
+func RedactBody(body []byte) string {
+	var review map[string]interface{}
+	if err := yaml.Unmarshal(body, &review); err != nil {
+		return fmt.Sprintf("<unparseable body of %d bytes>", len(body))
+	}
+
+	redactReview(review, func(m map[string]interface{}, key string) {
+		m[key] = redacted
+	})
+
+	bs, err := yaml.Marshal(review)
+	if err != nil {
+		return fmt.Sprintf("<unprintable body of %d bytes>", len(body))
+	}
+	return string(bs)
+}
+
+// RemoveSensitiveFields deletes user information and managed fields from an
+// unmarshalled AdmissionReview or ConversionReview. Unlike RedactBody, the
+// result still decodes as review.
+func RemoveSensitiveFields(review map[string]interface{}) {
+	redactReview(review, func(m map[string]interface{}, key string) {
+		delete(m, key)
+	})
+}
+
+func redactReview(review map[string]interface{}, redact func(m map[string]interface{}, key string)) {
+	if request, ok := review["request"].(map[string]interface{}); ok {
+		if _, ok := request["userInfo"]; ok {
+			redact(request, "userInfo")
+		}
+		for _, key := range []string{"object", "oldObject"} {
+			redactObject(request[key], redact)
+		}
+		if objects, ok := request["objects"].([]interface{}); ok {
+			for _, obj := range objects {
+				redactObject(obj, redact)
+			}
+		}
+	}
+	if response, ok := review["response"].(map[string]interface{}); ok {
+		if objects, ok := response["convertedObjects"].([]interface{}); ok {
+			for _, obj := range objects {
+				redactObject(obj, redact)
+			}
+		}
+	}
+}
+
+func redactObject(obj interface{}, redact func(m map[string]interface{}, key string)) {
+	o, ok := obj.(map[string]interface{})
+	if !ok {
+		return
+	}
+	metadata, ok := o["metadata"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if _, ok := metadata["managedFields"]; ok {
+		redact(metadata, "managedFields")
+	}
+	if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+		if _, ok := annotations["kubectl.kubernetes.io/last-applied-configuration"]; ok {
+			redact(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package webhook
+
+// Paths the webhook serves reviews on. The generated CRD and webhook
+// configurations point the API server to them.
+const (
+	ConvertPizzaPath      = "/convert/v1beta1/pizza"
+	AdmitPizzaPath        = "/admit/v1beta1/pizza"
+	ValidatePizzaPath     = "/validate/v1beta1/pizza"
+	ValidateOrderPath     = "/validate/v1beta1/order"
+	ValidatePromotionPath = "/validate/v1beta2/promotion"
+	ValidateRecipePath    = "/validate/v1beta2/pizzarecipe"
+)
@@ -0,0 +1,219 @@
+// Package record writes reviews and the responses to them to a local
+// directory, so that surprising webhook decisions can be replayed later.
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	"k8s.io/klog/v2"
+)
+
+// fileSuffix is the suffix of record files.
+const fileSuffix = ".json"
+
+// Record is a review and the response sent for it.
+type Record struct {
+	// Time is when the review was received.
+	Time time.Time `json:"time"`
+	// Path is the path the review was sent to, e.g. /admit/v1beta1/pizza.
+	Path string `json:"path"`
+	// Request is the AdmissionReview or ConversionReview.
+	Request json.RawMessage `json:"request"`
+	// ResponseCode is the HTTP status code of the response.
+	ResponseCode int `json:"responseCode"`
+	// Response is the review with the response, or the error text for codes other than 200.
+	Response json.RawMessage `json:"response"`
+}
+
+// Options configure a Recorder.
+type Options struct {
+	// Dir is the directory records are written to.
+	Dir string
+	// MaxFiles is the number of records kept. Older records are deleted.
+	MaxFiles int
+	// SampleRate is the fraction of reviews recorded, between 0 and 1.
+	SampleRate float64
+	// Redact removes user information and managed fields before writing.
+	Redact bool
+}
+
+// Recorder records reviews to a rotating directory.
+type Recorder struct {
+	opts Options
+
+	lock  sync.Mutex
+	seq   int
+	files []string
+}
+
+// New creates the record directory and picks up the records already in it
+// for rotation.
+func New(opts Options) (*Recorder, error) {
+	if opts.MaxFiles <= 0 {
+		return nil, fmt.Errorf("the maximum number of records must be positive, got %d", opts.MaxFiles)
+	}
+	if opts.SampleRate < 0 || opts.SampleRate > 1 {
+		return nil, fmt.Errorf("the sample rate must be between 0 and 1, got %v", opts.SampleRate)
+	}
+	if err := os.MkdirAll(opts.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create record directory: %w", err)
+	}
+	files, err := List(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{opts: opts, files: files}, nil
+}
+
+// List returns the record files in dir, oldest first.
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), fileSuffix) {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	// names start with a fixed-width timestamp
+	sort.Strings(files)
+	return files, nil
+}
+
+// Load reads a record file.
+func Load(filename string) (*Record, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	r := &Record{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("invalid record %s: %w", filename, err)
+	}
+	return r, nil
+}
+
+// WithRecording records the sampled reviews handler serves. Requests other
+// than POST, like health checks, are passed through.
+func (r *Recorder) WithRecording(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || rand.Float64() >= r.opts.SampleRate {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		body, err := webhook.ReadBody(req)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read request body: %v", err), webhook.ReadBodyErrorCode(err))
+			return
+		}
+		req.Body = readCloser{bytes.NewReader(body)}
+
+		rw := &responseRecorder{ResponseWriter: w, code: http.StatusOK}
+		received := time.Now()
+		handler.ServeHTTP(rw, req)
+
+		if err := r.write(received, req.URL.Path, body, rw.code, rw.body.Bytes()); err != nil {
+			klog.FromContext(req.Context()).Error(err, "Failed to record review")
+		}
+	})
+}
+
+func (r *Recorder) write(received time.Time, path string, request []byte, code int, response []byte) error {
+	rec := Record{
+		Time:         received,
+		Path:         path,
+		ResponseCode: code,
+	}
+	var err error
+	if rec.Request, err = r.redact(request); err != nil {
+		return fmt.Errorf("request: %w", err)
+	}
+	if code == http.StatusOK {
+		if rec.Response, err = r.redact(response); err != nil {
+			return fmt.Errorf("response: %w", err)
+		}
+	} else if rec.Response, err = json.Marshal(string(response)); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.seq++
+	name := fmt.Sprintf("%s-%06d-%s%s", received.UTC().Format("20060102T150405.000000000Z"), r.seq%1000000, strings.ReplaceAll(strings.Trim(path, "/"), "/", "-"), fileSuffix)
+	filename := filepath.Join(r.opts.Dir, name)
+	if err := os.WriteFile(filename, data, 0600); err != nil {
+		return err
+	}
+	r.files = append(r.files, filename)
+
+	for len(r.files) > r.opts.MaxFiles {
+		if err := os.Remove(r.files[0]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		r.files = r.files[1:]
+	}
+	return nil
+}
+
+// redact returns body as JSON, without sensitive fields if configured.
+func (r *Recorder) redact(body []byte) (json.RawMessage, error) {
+	if !r.opts.Redact {
+		if !json.Valid(body) {
+			return nil, fmt.Errorf("body is not JSON")
+		}
+		return body, nil
+	}
+	var review map[string]interface{}
+	if err := json.Unmarshal(body, &review); err != nil {
+		return nil, err
+	}
+	webhook.RemoveSensitiveFields(review)
+	return json.Marshal(review)
+}
+
+type readCloser struct {
+	*bytes.Reader
+}
+
+func (readCloser) Close() error { return nil }
+
+// responseRecorder copies the response written to a ResponseWriter.
+type responseRecorder struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.code = code
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
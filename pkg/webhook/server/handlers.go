@@ -0,0 +1,23 @@
+// Package server assembles the review handlers of the webhook.
+package server
+
+import (
+	"net/http"
+
+	restaurantinformers "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions"
+	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	"github.com/zeroisme/pizza-crd/pkg/webhook/admission"
+	"github.com/zeroisme/pizza-crd/pkg/webhook/conversion"
+)
+
+// InstallHandlers registers the review handlers on mux at the paths the
+// generated CRD and webhook configurations point to. The informers must be
+// started by the caller.
+func InstallHandlers(mux *http.ServeMux, informers restaurantinformers.SharedInformerFactory) {
+	mux.Handle(webhook.ConvertPizzaPath, http.HandlerFunc(conversion.Serve))
+	mux.Handle(webhook.AdmitPizzaPath, http.HandlerFunc(admission.ServePizzaAdmit(informers)))
+	mux.Handle(webhook.ValidatePizzaPath, http.HandlerFunc(admission.ServePizzaValidation(informers)))
+	mux.Handle(webhook.ValidateOrderPath, http.HandlerFunc(admission.ServeOrderValidation))
+	mux.Handle(webhook.ValidatePromotionPath, http.HandlerFunc(admission.ServePromotionValidation))
+	mux.Handle(webhook.ValidateRecipePath, http.HandlerFunc(admission.ServePizzaRecipeValidation(informers)))
+}
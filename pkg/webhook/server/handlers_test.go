@@ -0,0 +1,145 @@
+package server_test
+
+import (
+	"testing"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	webhooktesting "github.com/zeroisme/pizza-crd/pkg/webhook/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newServer(t *testing.T) *webhooktesting.Server {
+	return webhooktesting.NewServer(t, []runtime.Object{
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "tomato"}, Spec: v1alpha1.ToppingSpec{Cost: 0.5}},
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "mozzarella"}, Spec: v1alpha1.ToppingSpec{Cost: 0.8}},
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "salami"}, Spec: v1alpha1.ToppingSpec{Cost: 1}},
+	})
+}
+
+func TestAdmit(t *testing.T) {
+	s := newServer(t)
+	meta := metav1.ObjectMeta{Namespace: "default", Name: "margherita"}
+	tests := []struct {
+		name string
+		in   runtime.Object
+		want runtime.Object
+	}{
+		{
+			name: "v1alpha1",
+			in:   &v1alpha1.Pizza{ObjectMeta: meta},
+			want: &v1alpha1.Pizza{ObjectMeta: meta, Spec: v1alpha1.PizzaSpec{Toppings: []string{"tomato", "mozzarella", "salami"}}},
+		},
+		{
+			name: "v1beta1",
+			in:   &v1beta1.Pizza{ObjectMeta: meta},
+			want: &v1beta1.Pizza{ObjectMeta: meta, Spec: v1beta1.PizzaSpec{Toppings: []v1beta1.PizzaTopping{
+				{Name: "tomato", Quantity: 1}, {Name: "mozzarella", Quantity: 1}, {Name: "salami", Quantity: 1},
+			}}},
+		},
+		{
+			name: "v1beta2",
+			in:   &v1beta2.Pizza{ObjectMeta: meta, Spec: v1beta2.PizzaSpec{Toppings: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}}}},
+			want: &v1beta2.Pizza{ObjectMeta: meta, Spec: v1beta2.PizzaSpec{
+				Toppings: []v1beta2.PizzaTopping{{Name: "tomato", Quantity: 1}},
+				Size:     v1beta2.PizzaSizeMedium,
+			}},
+		},
+	}
+	for _, version := range webhooktesting.AdmissionReviewVersions {
+		for _, test := range tests {
+			t.Run(version+"/"+test.name, func(t *testing.T) {
+				review := webhooktesting.NewAdmissionReview(version, test.in).Build(t)
+				s.Admit(t, review).ExpectAllowed(t).ExpectPatchedTo(t, test.want)
+			})
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := newServer(t)
+	meta := metav1.ObjectMeta{Namespace: "default", Name: "margherita"}
+	for _, version := range webhooktesting.AdmissionReviewVersions {
+		t.Run(version, func(t *testing.T) {
+			review := webhooktesting.NewAdmissionReview(version, &v1alpha1.Pizza{
+				ObjectMeta: meta,
+				Spec:       v1alpha1.PizzaSpec{Toppings: []string{"tomato", "mozzarella"}},
+			}).Build(t)
+			s.Validate(t, review).ExpectAllowed(t)
+
+			review = webhooktesting.NewAdmissionReview(version, &v1beta1.Pizza{
+				ObjectMeta: meta,
+				Spec:       v1beta1.PizzaSpec{Toppings: []v1beta1.PizzaTopping{{Name: "pineapple", Quantity: 1}}},
+			}).Build(t)
+			s.Validate(t, review).ExpectDenied(t, `spec.toppings[0].name: Not found: "pineapple"`)
+		})
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	s := newServer(t)
+	order := func(phase v1beta1.OrderPhase) *v1beta1.Order {
+		return &v1beta1.Order{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "lunch"},
+			Spec: v1beta1.OrderSpec{
+				Pizzas: []v1beta1.OrderItem{{Name: "margherita", Quantity: 2}},
+				Phase:  phase,
+			},
+		}
+	}
+	for _, version := range webhooktesting.AdmissionReviewVersions {
+		t.Run(version, func(t *testing.T) {
+			review := webhooktesting.NewAdmissionReview(version, order("")).Build(t)
+			s.ValidateOrder(t, review).ExpectAllowed(t)
+
+			review = webhooktesting.NewAdmissionReview(version, order(v1beta1.OrderBaking)).WithOldObject(order(v1beta1.OrderPending)).Build(t)
+			s.ValidateOrder(t, review).ExpectDenied(t, "cannot move from Pending to Baking")
+		})
+	}
+}
+
+func TestValidatePromotion(t *testing.T) {
+	s := newServer(t)
+	percentOff := 10.0
+	amountOff := 1.0
+	promotion := func(discount v1beta2.PromotionDiscount) *v1beta2.Promotion {
+		return &v1beta2.Promotion{
+			ObjectMeta: metav1.ObjectMeta{Name: "salami-weeks"},
+			Spec: v1beta2.PromotionSpec{
+				Selector: v1beta2.PromotionSelector{Toppings: []string{"salami"}},
+				Discount: discount,
+			},
+		}
+	}
+	for _, version := range webhooktesting.AdmissionReviewVersions {
+		t.Run(version, func(t *testing.T) {
+			review := webhooktesting.NewAdmissionReview(version, promotion(v1beta2.PromotionDiscount{PercentOff: &percentOff})).Build(t)
+			s.ValidatePromotion(t, review).ExpectAllowed(t)
+
+			review = webhooktesting.NewAdmissionReview(version, promotion(v1beta2.PromotionDiscount{PercentOff: &percentOff, AmountOff: &amountOff})).Build(t)
+			s.ValidatePromotion(t, review).ExpectDenied(t, "may not be set together with percentOff")
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	s := newServer(t)
+	meta := metav1.ObjectMeta{Namespace: "default", Name: "margherita"}
+	for _, version := range webhooktesting.ConversionReviewVersions {
+		t.Run(version, func(t *testing.T) {
+			review := webhooktesting.NewConversionReview(version, v1alpha1.SchemeGroupVersion.String(),
+				&v1beta1.Pizza{ObjectMeta: meta, Spec: v1beta1.PizzaSpec{Toppings: []v1beta1.PizzaTopping{{Name: "salami", Quantity: 2}}}},
+			).Build(t)
+			s.Convert(t, review).ExpectSuccess(t).ExpectConverted(t,
+				&v1alpha1.Pizza{ObjectMeta: meta, Spec: v1alpha1.PizzaSpec{Toppings: []string{"salami", "salami"}}},
+			)
+
+			review = webhooktesting.NewConversionReview(version, "restaurant.programming-kubernetes.info/v2",
+				&v1beta1.Pizza{ObjectMeta: meta},
+			).Build(t)
+			s.Convert(t, review).ExpectFailure(t, "cannot convert")
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package testing
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/google/go-cmp/cmp"
+	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// AdmissionResult is the response to an AdmissionReview. Responses of
+// v1beta1 reviews are converted to v1, which has the same fields.
+type AdmissionResult struct {
+	// Response is the response of the review.
+	Response *admissionv1.AdmissionResponse
+	// object is the object of the request.
+	object []byte
+}
+
+func newAdmissionResult(t testing.TB, review, respObj runtime.Object) *AdmissionResult {
+	t.Helper()
+	r := &AdmissionResult{}
+	switch review := review.(type) {
+	case *admissionv1.AdmissionReview:
+		r.object = review.Request.Object.Raw
+	case *admissionv1beta1.AdmissionReview:
+		r.object = review.Request.Object.Raw
+	default:
+		t.Fatalf("unexpected review %T", review)
+	}
+
+	switch resp := respObj.(type) {
+	case *admissionv1.AdmissionReview:
+		r.Response = resp.Response
+	case *admissionv1beta1.AdmissionReview:
+		if resp.Response != nil {
+			r.Response = &admissionv1.AdmissionResponse{
+				UID:              resp.Response.UID,
+				Allowed:          resp.Response.Allowed,
+				Result:           resp.Response.Result,
+				Patch:            resp.Response.Patch,
+				AuditAnnotations: resp.Response.AuditAnnotations,
+				Warnings:         resp.Response.Warnings,
+			}
+			if resp.Response.PatchType != nil {
+				patchType := admissionv1.PatchType(*resp.Response.PatchType)
+				r.Response.PatchType = &patchType
+			}
+		}
+	default:
+		t.Fatalf("unexpected response %T", respObj)
+	}
+	if r.Response == nil {
+		t.Fatalf("response review of %T has no response", review)
+	}
+	return r
+}
+
+// ExpectAllowed fails the test if the review was denied.
+func (r *AdmissionResult) ExpectAllowed(t testing.TB) *AdmissionResult {
+	t.Helper()
+	if !r.Response.Allowed {
+		t.Fatalf("expected review to be allowed, got denied: %s", resultMessage(r.Response.Result))
+	}
+	return r
+}
+
+// ExpectDenied fails the test if the review was allowed or the denial message
+// does not contain substr.
+func (r *AdmissionResult) ExpectDenied(t testing.TB, substr string) *AdmissionResult {
+	t.Helper()
+	if r.Response.Allowed {
+		t.Fatalf("expected review to be denied with %q, got allowed", substr)
+	}
+	if msg := resultMessage(r.Response.Result); !strings.Contains(msg, substr) {
+		t.Fatalf("expected denial message to contain %q, got %q", substr, msg)
+	}
+	return r
+}
+
+// ExpectWarning fails the test if no warning contains substr.
+func (r *AdmissionResult) ExpectWarning(t testing.TB, substr string) *AdmissionResult {
+	t.Helper()
+	for _, w := range r.Response.Warnings {
+		if strings.Contains(w, substr) {
+			return r
+		}
+	}
+	t.Fatalf("expected a warning containing %q, got %q", substr, r.Response.Warnings)
+	return r
+}
+
+// ExpectNoPatch fails the test if the response has a patch.
+func (r *AdmissionResult) ExpectNoPatch(t testing.TB) *AdmissionResult {
+	t.Helper()
+	if len(r.Response.Patch) > 0 {
+		t.Fatalf("expected no patch, got %s", r.Response.Patch)
+	}
+	return r
+}
+
+// Patched returns the request object with the JSON patch of the response
+// applied. Without patch it is the unchanged request object.
+func (r *AdmissionResult) Patched(t testing.TB) runtime.Object {
+	t.Helper()
+	patched := r.object
+	if len(r.Response.Patch) > 0 {
+		if r.Response.PatchType == nil || *r.Response.PatchType != admissionv1.PatchTypeJSONPatch {
+			t.Fatalf("unexpected patch type %v", r.Response.PatchType)
+		}
+		patch, err := jsonpatch.DecodePatch(r.Response.Patch)
+		if err != nil {
+			t.Fatalf("invalid patch %s: %v", r.Response.Patch, err)
+		}
+		if patched, err = patch.Apply(r.object); err != nil {
+			t.Fatalf("failed to apply patch %s: %v", r.Response.Patch, err)
+		}
+	}
+	obj, _, err := webhook.Codecs.UniversalDeserializer().Decode(patched, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to decode patched object: %v\n%s", err, patched)
+	}
+	return obj
+}
+
+// ExpectPatchedTo fails the test if the patched request object differs from
+// want. The kind of want is filled in from the scheme if unset.
+func (r *AdmissionResult) ExpectPatchedTo(t testing.TB, want runtime.Object) *AdmissionResult {
+	t.Helper()
+	want = want.DeepCopyObject()
+	want.GetObjectKind().SetGroupVersionKind(objectKind(t, want))
+	got := r.Patched(t)
+	if !equality.Semantic.DeepEqual(want, got) {
+		t.Fatalf("unexpected patched object (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	return r
+}
+
+// ConversionResult is the response to a ConversionReview. Responses of
+// v1beta1 reviews are converted to v1, which has the same fields.
+type ConversionResult struct {
+	// Response is the response of the review.
+	Response *apiextensionsv1.ConversionResponse
+}
+
+func newConversionResult(t testing.TB, respObj runtime.Object) *ConversionResult {
+	t.Helper()
+	r := &ConversionResult{}
+	switch resp := respObj.(type) {
+	case *apiextensionsv1.ConversionReview:
+		r.Response = resp.Response
+	case *apiextensionsv1beta1.ConversionReview:
+		if resp.Response != nil {
+			r.Response = &apiextensionsv1.ConversionResponse{
+				UID:              resp.Response.UID,
+				ConvertedObjects: resp.Response.ConvertedObjects,
+				Result:           resp.Response.Result,
+			}
+		}
+	default:
+		t.Fatalf("unexpected response %T", respObj)
+	}
+	if r.Response == nil {
+		t.Fatalf("response review of %T has no response", respObj)
+	}
+	return r
+}
+
+// ExpectSuccess fails the test if the conversion failed.
+func (r *ConversionResult) ExpectSuccess(t testing.TB) *ConversionResult {
+	t.Helper()
+	if r.Response.Result.Status != metav1.StatusSuccess {
+		t.Fatalf("expected conversion to succeed, got %s", resultMessage(&r.Response.Result))
+	}
+	return r
+}
+
+// ExpectFailure fails the test if the conversion succeeded or the message
+// does not contain substr.
+func (r *ConversionResult) ExpectFailure(t testing.TB, substr string) *ConversionResult {
+	t.Helper()
+	if r.Response.Result.Status == metav1.StatusSuccess {
+		t.Fatalf("expected conversion to fail with %q, got success", substr)
+	}
+	if msg := resultMessage(&r.Response.Result); !strings.Contains(msg, substr) {
+		t.Fatalf("expected conversion failure to contain %q, got %q", substr, msg)
+	}
+	return r
+}
+
+// Objects returns the converted objects.
+func (r *ConversionResult) Objects(t testing.TB) []runtime.Object {
+	t.Helper()
+	objs := make([]runtime.Object, 0, len(r.Response.ConvertedObjects))
+	for i, raw := range r.Response.ConvertedObjects {
+		obj, _, err := webhook.Codecs.UniversalDeserializer().Decode(raw.Raw, nil, nil)
+		if err != nil {
+			t.Fatalf("failed to decode converted object %d: %v\n%s", i, err, raw.Raw)
+		}
+		objs = append(objs, obj)
+	}
+	return objs
+}
+
+// ExpectConverted fails the test if the converted objects differ from want.
+// The kinds of want are filled in from the scheme if unset.
+func (r *ConversionResult) ExpectConverted(t testing.TB, want ...runtime.Object) *ConversionResult {
+	t.Helper()
+	for i := range want {
+		want[i] = want[i].DeepCopyObject()
+		want[i].GetObjectKind().SetGroupVersionKind(objectKind(t, want[i]))
+	}
+	got := r.Objects(t)
+	if !equality.Semantic.DeepEqual(want, got) {
+		t.Fatalf("unexpected converted objects (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	return r
+}
+
+func resultMessage(status *metav1.Status) string {
+	if status == nil || reflect.DeepEqual(*status, metav1.Status{}) {
+		return "<no result>"
+	}
+	return status.Message
+}
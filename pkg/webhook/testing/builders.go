@@ -0,0 +1,226 @@
+package testing
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// AdmissionReviewVersions are the AdmissionReview versions the webhook accepts.
+var AdmissionReviewVersions = []string{"v1", "v1beta1"}
+
+// ConversionReviewVersions are the ConversionReview versions the webhook accepts.
+var ConversionReviewVersions = []string{"v1", "v1beta1"}
+
+// AdmissionReviewBuilder builds an AdmissionReview of a given version.
+type AdmissionReviewBuilder struct {
+	version     string
+	uid         types.UID
+	operation   admissionv1.Operation
+	object      runtime.Object
+	oldObject   runtime.Object
+	userInfo    authenticationv1.UserInfo
+	subResource string
+	dryRun      bool
+}
+
+// NewAdmissionReview returns a builder for an AdmissionReview of version, v1
+// or v1beta1, creating object.
+func NewAdmissionReview(version string, object runtime.Object) *AdmissionReviewBuilder {
+	return &AdmissionReviewBuilder{
+		version:   version,
+		uid:       "test-uid",
+		operation: admissionv1.Create,
+		object:    object,
+		userInfo:  authenticationv1.UserInfo{Username: "tester"},
+	}
+}
+
+// WithUID sets the UID of the request.
+func (b *AdmissionReviewBuilder) WithUID(uid types.UID) *AdmissionReviewBuilder {
+	b.uid = uid
+	return b
+}
+
+// WithOperation sets the operation, CREATE by default.
+func (b *AdmissionReviewBuilder) WithOperation(operation admissionv1.Operation) *AdmissionReviewBuilder {
+	b.operation = operation
+	return b
+}
+
+// WithOldObject sets the object before an UPDATE and makes the request an UPDATE.
+func (b *AdmissionReviewBuilder) WithOldObject(oldObject runtime.Object) *AdmissionReviewBuilder {
+	b.oldObject = oldObject
+	b.operation = admissionv1.Update
+	return b
+}
+
+// WithUserInfo sets the requesting user.
+func (b *AdmissionReviewBuilder) WithUserInfo(userInfo authenticationv1.UserInfo) *AdmissionReviewBuilder {
+	b.userInfo = userInfo
+	return b
+}
+
+// WithSubResource makes the request one for a subresource, e.g. status.
+func (b *AdmissionReviewBuilder) WithSubResource(subResource string) *AdmissionReviewBuilder {
+	b.subResource = subResource
+	return b
+}
+
+// WithDryRun marks the request as dry-run.
+func (b *AdmissionReviewBuilder) WithDryRun() *AdmissionReviewBuilder {
+	b.dryRun = true
+	return b
+}
+
+// Build returns the AdmissionReview.
+func (b *AdmissionReviewBuilder) Build(t testing.TB) runtime.Object {
+	t.Helper()
+	gvk := objectKind(t, b.object)
+	accessor, err := meta.Accessor(b.object)
+	if err != nil {
+		t.Fatalf("invalid object %T: %v", b.object, err)
+	}
+	request := admissionv1.AdmissionRequest{
+		UID:         b.uid,
+		Kind:        metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+		Resource:    metav1.GroupVersionResource{Group: gvk.Group, Version: gvk.Version, Resource: resourceName(gvk)},
+		SubResource: b.subResource,
+		Name:        accessor.GetName(),
+		Namespace:   accessor.GetNamespace(),
+		Operation:   b.operation,
+		UserInfo:    b.userInfo,
+		Object:      runtime.RawExtension{Raw: encode(t, b.object)},
+		DryRun:      &b.dryRun,
+	}
+	if b.oldObject != nil {
+		request.OldObject = runtime.RawExtension{Raw: encode(t, b.oldObject)}
+	}
+
+	switch b.version {
+	case "v1":
+		return &admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: admissionv1.SchemeGroupVersion.String(), Kind: "AdmissionReview"},
+			Request:  &request,
+		}
+	case "v1beta1":
+		return &admissionv1beta1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: admissionv1beta1.SchemeGroupVersion.String(), Kind: "AdmissionReview"},
+			Request: &admissionv1beta1.AdmissionRequest{
+				UID:         request.UID,
+				Kind:        request.Kind,
+				Resource:    request.Resource,
+				SubResource: request.SubResource,
+				Name:        request.Name,
+				Namespace:   request.Namespace,
+				Operation:   admissionv1beta1.Operation(request.Operation),
+				UserInfo:    request.UserInfo,
+				Object:      request.Object,
+				OldObject:   request.OldObject,
+				DryRun:      request.DryRun,
+			},
+		}
+	default:
+		t.Fatalf("unsupported AdmissionReview version %q", b.version)
+		return nil
+	}
+}
+
+// ConversionReviewBuilder builds a ConversionReview of a given version.
+type ConversionReviewBuilder struct {
+	version           string
+	uid               types.UID
+	desiredAPIVersion string
+	objects           []runtime.Object
+}
+
+// NewConversionReview returns a builder for a ConversionReview of version, v1
+// or v1beta1, converting objects to desiredAPIVersion.
+func NewConversionReview(version, desiredAPIVersion string, objects ...runtime.Object) *ConversionReviewBuilder {
+	return &ConversionReviewBuilder{
+		version:           version,
+		uid:               "test-uid",
+		desiredAPIVersion: desiredAPIVersion,
+		objects:           objects,
+	}
+}
+
+// WithUID sets the UID of the request.
+func (b *ConversionReviewBuilder) WithUID(uid types.UID) *ConversionReviewBuilder {
+	b.uid = uid
+	return b
+}
+
+// Build returns the ConversionReview.
+func (b *ConversionReviewBuilder) Build(t testing.TB) runtime.Object {
+	t.Helper()
+	objects := make([]runtime.RawExtension, 0, len(b.objects))
+	for _, obj := range b.objects {
+		objects = append(objects, runtime.RawExtension{Raw: encode(t, obj)})
+	}
+
+	switch b.version {
+	case "v1":
+		return &apiextensionsv1.ConversionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: apiextensionsv1.SchemeGroupVersion.String(), Kind: "ConversionReview"},
+			Request: &apiextensionsv1.ConversionRequest{
+				UID:               b.uid,
+				DesiredAPIVersion: b.desiredAPIVersion,
+				Objects:           objects,
+			},
+		}
+	case "v1beta1":
+		return &apiextensionsv1beta1.ConversionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: apiextensionsv1beta1.SchemeGroupVersion.String(), Kind: "ConversionReview"},
+			Request: &apiextensionsv1beta1.ConversionRequest{
+				UID:               b.uid,
+				DesiredAPIVersion: b.desiredAPIVersion,
+				Objects:           objects,
+			},
+		}
+	default:
+		t.Fatalf("unsupported ConversionReview version %q", b.version)
+		return nil
+	}
+}
+
+// objectKind returns the kind of obj, from its TypeMeta or the scheme.
+func objectKind(t testing.TB, obj runtime.Object) schema.GroupVersionKind {
+	t.Helper()
+	if gvk := obj.GetObjectKind().GroupVersionKind(); !gvk.Empty() {
+		return gvk
+	}
+	gvks, _, err := webhook.Scheme.ObjectKinds(obj)
+	if err != nil {
+		t.Fatalf("unknown object %T: %v", obj, err)
+	}
+	return gvks[0]
+}
+
+// encode returns obj as JSON with apiVersion and kind set.
+func encode(t testing.TB, obj runtime.Object) []byte {
+	t.Helper()
+	obj = obj.DeepCopyObject()
+	obj.GetObjectKind().SetGroupVersionKind(objectKind(t, obj))
+	bs, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("failed to encode %T: %v", obj, err)
+	}
+	return bs
+}
+
+func resourceName(gvk schema.GroupVersionKind) string {
+	return strings.ToLower(gvk.Kind) + "s"
+}
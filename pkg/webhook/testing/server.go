@@ -0,0 +1,168 @@
+// Package testing runs the webhook in-process for tests. A Server serves the
+// real handlers over TLS, backed by the generated fake clientset and
+// informers, and the builders and assertions send reviews in every supported
+// version and check the responses:
+//
+//	func TestDefaultToppings(t *testing.T) {
+//		s := webhooktesting.NewServer(t, []runtime.Object{salami, tomato, mozzarella})
+//		for _, version := range webhooktesting.AdmissionReviewVersions {
+//			review := webhooktesting.NewAdmissionReview(version, &v1beta1.Pizza{...}).Build(t)
+//			s.Admit(t, review).ExpectAllowed(t).ExpectPatchedTo(t, &v1beta1.Pizza{...})
+//		}
+//	}
+package testing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zeroisme/pizza-crd/pkg/generated/clientset/versioned/fake"
+	restaurantinformers "github.com/zeroisme/pizza-crd/pkg/generated/informers/externalversions"
+	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	webhookserver "github.com/zeroisme/pizza-crd/pkg/webhook/server"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/component-base/tracing"
+)
+
+// Server is the webhook serving on a local TLS port.
+type Server struct {
+	// Server is the underlying test server.
+	*httptest.Server
+	// Clientset is the fake clientset the informers list from. Objects
+	// created with it are seen by the handlers once the informers caught up.
+	Clientset *fake.Clientset
+	// Informers are the started informers of the handlers.
+	Informers restaurantinformers.SharedInformerFactory
+}
+
+// Option configures a Server.
+type Option func(*options)
+
+type options struct {
+	tracerProvider oteltrace.TracerProvider
+}
+
+// WithTracerProvider traces the reviews with tp, e.g. with an in-memory
+// exporter to assert on spans.
+func WithTracerProvider(tp oteltrace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = tp
+	}
+}
+
+// NewServer starts the webhook with the given objects, usually Toppings, in
+// the fake clientset. The server is stopped when the test finishes.
+func NewServer(t testing.TB, objects []runtime.Object, opts ...Option) *Server {
+	t.Helper()
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	clientset := fake.NewSimpleClientset(objects...)
+	informers := restaurantinformers.NewSharedInformerFactory(clientset, 0)
+	mux := http.NewServeMux()
+	webhookserver.InstallHandlers(mux, informers)
+
+	var handler http.Handler = mux
+	if o.tracerProvider != nil {
+		handler = tracing.WithTracing(handler, o.tracerProvider, "pizza-crd-webhook")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	informers.Start(ctx.Done())
+	for typ, synced := range informers.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			t.Fatalf("informer for %v did not sync", typ)
+		}
+	}
+
+	s := &Server{
+		Server:    httptest.NewTLSServer(handler),
+		Clientset: clientset,
+		Informers: informers,
+	}
+	t.Cleanup(func() {
+		s.Close()
+		cancel()
+		informers.Shutdown()
+	})
+	return s
+}
+
+// Admit sends an AdmissionReview to the mutating webhook.
+func (s *Server) Admit(t testing.TB, review runtime.Object) *AdmissionResult {
+	t.Helper()
+	return newAdmissionResult(t, review, s.post(t, webhook.AdmitPizzaPath, review))
+}
+
+// Validate sends an AdmissionReview to the validating webhook.
+func (s *Server) Validate(t testing.TB, review runtime.Object) *AdmissionResult {
+	t.Helper()
+	return newAdmissionResult(t, review, s.post(t, webhook.ValidatePizzaPath, review))
+}
+
+// ValidateOrder sends an AdmissionReview to the validating webhook of Orders.
+func (s *Server) ValidateOrder(t testing.TB, review runtime.Object) *AdmissionResult {
+	t.Helper()
+	return newAdmissionResult(t, review, s.post(t, webhook.ValidateOrderPath, review))
+}
+
+// ValidatePromotion sends an AdmissionReview to the validating webhook of
+// Promotions.
+func (s *Server) ValidatePromotion(t testing.TB, review runtime.Object) *AdmissionResult {
+	t.Helper()
+	return newAdmissionResult(t, review, s.post(t, webhook.ValidatePromotionPath, review))
+}
+
+// ValidateRecipe sends an AdmissionReview to the validating webhook of
+// PizzaRecipes.
+func (s *Server) ValidateRecipe(t testing.TB, review runtime.Object) *AdmissionResult {
+	t.Helper()
+	return newAdmissionResult(t, review, s.post(t, webhook.ValidateRecipePath, review))
+}
+
+// Convert sends a ConversionReview to the conversion webhook.
+func (s *Server) Convert(t testing.TB, review runtime.Object) *ConversionResult {
+	t.Helper()
+	return newConversionResult(t, s.post(t, webhook.ConvertPizzaPath, review))
+}
+
+// post sends a review and returns the decoded response review.
+func (s *Server) post(t testing.TB, path string, review runtime.Object) runtime.Object {
+	t.Helper()
+	body, err := runtime.Encode(webhook.Codecs.LegacyCodec(review.GetObjectKind().GroupVersionKind().GroupVersion()), review)
+	if err != nil {
+		t.Fatalf("failed to encode %T: %v", review, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.URL+path, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatalf("POST %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response of POST %s: %v", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST %s: %s: %s", path, resp.Status, respBody)
+	}
+
+	obj, _, err := webhook.Codecs.UniversalDeserializer().Decode(respBody, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to decode response of POST %s: %v\n%s", path, err, respBody)
+	}
+	return obj
+}
@@ -0,0 +1,26 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/component-base/tracing"
+)
+
+// TraceThreshold is the duration above which a span is also logged.
+const TraceThreshold = 500 * time.Millisecond
+
+// Decode decodes data inside a tracing span, which records the decoded kind.
+func Decode(ctx context.Context, decoder runtime.Decoder, data []byte) (runtime.Object, *schema.GroupVersionKind, error) {
+	ctx, span := tracing.Start(ctx, "Decode", attribute.Int("size", len(data)))
+	defer span.End(TraceThreshold)
+	obj, gvk, err := decoder.Decode(data, nil, nil)
+	if gvk != nil {
+		oteltrace.SpanFromContext(ctx).SetAttributes(attribute.String("kind", gvk.String()))
+	}
+	return obj, gvk, err
+}
@@ -0,0 +1,157 @@
+package webhook_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1alpha1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta1"
+	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/v1beta2"
+	"github.com/zeroisme/pizza-crd/pkg/webhook"
+	webhooktesting "github.com/zeroisme/pizza-crd/pkg/webhook/testing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// serverSpanName is the name of the span of a request to the webhook.
+const serverSpanName = "pizza-crd-webhook"
+
+func newTracedServer(t *testing.T) (*webhooktesting.Server, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	s := webhooktesting.NewServer(t, []runtime.Object{
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "tomato"}, Spec: v1alpha1.ToppingSpec{Cost: 0.5}},
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "salami"}, Spec: v1alpha1.ToppingSpec{Cost: 1}},
+		&v1alpha1.Topping{ObjectMeta: metav1.ObjectMeta{Name: "mozzarella"}, Spec: v1alpha1.ToppingSpec{Cost: 0.8}},
+	}, webhooktesting.WithTracerProvider(tp))
+	return s, exporter
+}
+
+// requestSpans waits for the span of the request to the webhook to end and
+// returns the spans of the request by name.
+func requestSpans(t *testing.T, exporter *tracetest.InMemoryExporter) map[string][]tracetest.SpanStub {
+	t.Helper()
+	var spans map[string][]tracetest.SpanStub
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		spans = map[string][]tracetest.SpanStub{}
+		for _, span := range exporter.GetSpans() {
+			spans[span.Name] = append(spans[span.Name], span)
+		}
+		return len(spans[serverSpanName]) > 0, nil
+	})
+	if err != nil {
+		t.Fatalf("request span did not end: %v", err)
+	}
+	exporter.Reset()
+	return spans
+}
+
+// expectChild returns a span with the name which is a child of parent, and
+// fails the test if there is none.
+func expectChild(t *testing.T, spans map[string][]tracetest.SpanStub, name string, parent tracetest.SpanStub) tracetest.SpanStub {
+	t.Helper()
+	if len(spans[name]) == 0 {
+		t.Fatalf("no %q span, got %v", name, spanNames(spans))
+	}
+	for _, span := range spans[name] {
+		if span.Parent.SpanID() == parent.SpanContext.SpanID() {
+			return span
+		}
+	}
+	t.Fatalf("no %q span is a child of the %q span", name, parent.Name)
+	return tracetest.SpanStub{}
+}
+
+func spanNames(spans map[string][]tracetest.SpanStub) []string {
+	var names []string
+	for name := range spans {
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestTracing(t *testing.T) {
+	s, exporter := newTracedServer(t)
+	pizza := &v1beta1.Pizza{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "margherita"},
+		Spec:       v1beta1.PizzaSpec{Toppings: []v1beta1.PizzaTopping{{Name: "tomato", Quantity: 1}, {Name: "mozzarella", Quantity: 1}}},
+	}
+
+	t.Run("admit", func(t *testing.T) {
+		s.Admit(t, webhooktesting.NewAdmissionReview("v1", pizza).Build(t)).ExpectAllowed(t)
+		spans := requestSpans(t, exporter)
+		server := spans[serverSpanName][0]
+		expectChild(t, spans, "Decode", server)
+		expectChild(t, spans, "Default pizza", server)
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		s.Validate(t, webhooktesting.NewAdmissionReview("v1", pizza).Build(t)).ExpectAllowed(t)
+		spans := requestSpans(t, exporter)
+		server := spans[serverSpanName][0]
+		validate := expectChild(t, spans, "Validate pizza", server)
+		expectChild(t, spans, "Lookup topping", validate)
+		for _, span := range spans["Lookup topping"] {
+			if span.Parent.SpanID() != validate.SpanContext.SpanID() {
+				t.Errorf("%q span is not a child of the %q span", span.Name, validate.Name)
+			}
+		}
+	})
+
+	t.Run("convert", func(t *testing.T) {
+		review := webhooktesting.NewConversionReview("v1", v1beta2.SchemeGroupVersion.String(), pizza).Build(t)
+		s.Convert(t, review).ExpectSuccess(t)
+		spans := requestSpans(t, exporter)
+		server := spans[serverSpanName][0]
+		convert := expectChild(t, spans, "Convert object", server)
+		expectChild(t, spans, "Decode", convert)
+	})
+}
+
+func TestTracingPropagation(t *testing.T) {
+	s, exporter := newTracedServer(t)
+	traceID, _ := oteltrace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	parentID, _ := oteltrace.SpanIDFromHex("00f067aa0ba902b7")
+
+	review := webhooktesting.NewAdmissionReview("v1", &v1beta1.Pizza{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "margherita"},
+		Spec:       v1beta1.PizzaSpec{Toppings: []v1beta1.PizzaTopping{{Name: "tomato", Quantity: 1}}},
+	}).Build(t)
+	body, err := runtime.Encode(webhook.Codecs.LegacyCodec(review.GetObjectKind().GroupVersionKind().GroupVersion()), review)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, s.URL+webhook.AdmitPizzaPath, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", "00-"+traceID.String()+"-"+parentID.String()+"-01")
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %s", resp.Status)
+	}
+
+	spans := requestSpans(t, exporter)
+	server := spans[serverSpanName][0]
+	if got := server.Parent.SpanID(); got != parentID {
+		t.Errorf("expected the request span to have the parent %s of the traceparent header, got %s", parentID, got)
+	}
+	for name, byName := range spans {
+		for _, span := range byName {
+			if got := span.SpanContext.TraceID(); got != traceID {
+				t.Errorf("expected %q span in trace %s, got %s", name, traceID, got)
+			}
+		}
+	}
+}
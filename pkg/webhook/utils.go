@@ -1,6 +1,7 @@
 package webhook
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 	"strings"
@@ -8,7 +9,7 @@ import (
 	"github.com/munnerz/goautoneg"
 	"github.com/zeroisme/pizza-crd/pkg/apis/restaurant/install"
 	admissionv1 "k8s.io/api/admission/v1"
-	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -69,19 +70,23 @@ func GetOutputSerializer(accept string) runtime.Serializer {
 }
 
 func SendResponse(w http.ResponseWriter, r *http.Request, obj runtime.Object) {
-	var err error
+	logger := klog.FromContext(r.Context())
 	accept := r.Header.Get("Accept")
 	outSerializer := GetOutputSerializer(accept)
 	if outSerializer == nil {
 		msg := fmt.Sprintf("invalid Accept header `%s`", accept)
-		klog.Errorf(msg)
+		logger.Error(nil, "Rejecting malformed request", "reason", msg)
 		http.Error(w, msg, http.StatusBadRequest)
 		return
 	}
-	err = outSerializer.Encode(obj, w)
-	if err != nil {
-		klog.Error(err)
+	var buf bytes.Buffer
+	if err := outSerializer.Encode(obj, &buf); err != nil {
+		logger.Error(err, "Failed to encode response")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	LogBody(logger, "Sending response", buf.Bytes())
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		logger.Error(err, "Failed to write response")
+	}
 }